@@ -0,0 +1,45 @@
+package mo
+
+import "context"
+
+type fieldsKey struct{}
+
+// ContextWithFields returns a copy of ctx carrying the given key-value pairs in
+// addition to those already carried by ctx. Loggers add the fields carried by the
+// context of a log call to the log message, after the base fields.
+func ContextWithFields(ctx context.Context, kv ...Field) context.Context {
+	parent := FieldsFromContext(ctx)
+	fields := make([]Field, 0, len(parent)+len(kv))
+	fields = append(fields, parent...)
+	fields = append(fields, kv...)
+	return context.WithValue(ctx, fieldsKey{}, fields)
+}
+
+// FieldsFromContext returns the key-value pairs carried by ctx.
+// The returned slice must not be modified.
+func FieldsFromContext(ctx context.Context) []Field {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(fieldsKey{}).([]Field)
+	return fields
+}
+
+type helperKey struct{}
+
+// NewContext returns a copy of ctx carrying the helper, see FromContext.
+func NewContext(ctx context.Context, h *Helper) context.Context {
+	return context.WithValue(ctx, helperKey{}, h)
+}
+
+// FromContext returns the Helper carried by ctx, or the default Helper if ctx carries none.
+// The returned Helper logs with ctx, so the fields and values carried by ctx are included.
+func FromContext(ctx context.Context) *Helper {
+	if ctx == nil {
+		return Default()
+	}
+	if h, ok := ctx.Value(helperKey{}).(*Helper); ok && h != nil {
+		return h.With(ctx)
+	}
+	return Default().With(ctx)
+}
@@ -0,0 +1,80 @@
+package mo
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// keys returns the fields as key=value pairs joined by commas.
+func keys(kv []Field) string {
+	items := make([]string, len(kv))
+	for i, v := range kv {
+		items[i] = v.Key() + "=" + string(v.AppendValue(nil))
+	}
+	return strings.Join(items, ",")
+}
+
+func TestContextWithFields(t *testing.T) {
+	if FieldsFromContext(context.Background()) != nil {
+		t.Error("empty context should carry no fields")
+	}
+
+	parent := ContextWithFields(context.Background(), String("a", "1"), String("b", "1"))
+	child := ContextWithFields(parent, String("b", "2"), String("c", "2"))
+	sibling := ContextWithFields(parent, String("d", "3"))
+	if got := keys(FieldsFromContext(child)); got != "a=1,b=1,b=2,c=2" {
+		t.Errorf("child fields = %s", got)
+	}
+	if got := keys(FieldsFromContext(sibling)); got != "a=1,b=1,d=3" {
+		t.Errorf("sibling fields = %s", got)
+	}
+	if got := keys(FieldsFromContext(parent)); got != "a=1,b=1" {
+		t.Errorf("parent fields changed to %s", got)
+	}
+
+	// Loggers add the name, base, context and call fields in that order.
+	r := &fieldsRecorder{}
+	logger := NewLogger(r).Named("app").With(String("base", "0"))
+	logger.Printw(child, LevelInfo, "msg", String("call", "4"))
+	if got := keys(r.kv); got != "logger=app,base=0,a=1,b=1,b=2,c=2,call=4" {
+		t.Errorf("logged fields = %s", got)
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	r := &fieldsRecorder{}
+	prev := Default()
+	SetDefault(New(context.Background(), NewLogger(r)))
+	defer SetDefault(prev)
+
+	// Without a Helper in the context, the default Helper is used with ctx.
+	ctx := ContextWithFields(context.Background(), String("req", "1"))
+	if h := FromContext(ctx); h.Logger != Default().Logger || h.ctx != ctx {
+		t.Error("FromContext should fall back to the default Helper bound to ctx")
+	}
+	FromContext(ctx).Info("msg")
+	if got := keys(r.kv); got != "req=1" {
+		t.Errorf("logged fields = %s", got)
+	}
+	if FromContext(nil) != Default() {
+		t.Error("FromContext(nil) should return the default Helper")
+	}
+
+	// A Helper carried by ctx is rebound to ctx, so later context fields are logged.
+	other := &fieldsRecorder{}
+	h := New(context.Background(), NewLogger(other)).WithFields(String("svc", "api"))
+	ctx = NewContext(context.Background(), h)
+	ctx = ContextWithFields(ctx, String("req", "2"))
+	got := FromContext(ctx)
+	if got.Logger != h.Logger || got.ctx != ctx {
+		t.Fatal("FromContext should return the carried Helper bound to ctx")
+	}
+	got.Info("msg")
+	if got := keys(other.kv); got != "svc=api,req=2" {
+		t.Errorf("logged fields = %s", got)
+	}
+	if FromContext(NewContext(ctx, nil)).Logger != Default().Logger {
+		t.Error("a nil Helper in ctx should fall back to the default Helper")
+	}
+}
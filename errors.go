@@ -0,0 +1,93 @@
+package mo
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ErrorFielder is implemented by errors that carry key-value pairs for logging.
+// Recorders add the fields of an error field's error and of its causes to the log message.
+type ErrorFielder interface {
+	LogFields() []Field
+}
+
+// ErrorCause is an error in the chain of causes of a logged error.
+type ErrorCause struct {
+	Message string // Error message
+	Type    string // Concrete type, such as "*fs.PathError"
+}
+
+// ErrorDetails describes a logged error, see DescribeError.
+type ErrorDetails struct {
+	Message string       // Error message
+	Type    string       // Concrete type, such as "*fs.PathError"
+	Causes  []ErrorCause // Errors unwrapped from the error, depth first
+	Fields  []Field      // Fields of the error and its causes implementing ErrorFielder
+}
+
+// ErrorDescriber is implemented by errors that describe themselves, such as errors
+// rewritten by recorders to hide parts of the original error. DescribeError returns
+// the result of DescribeError for them.
+type ErrorDescriber interface {
+	DescribeError() ErrorDetails
+}
+
+// maxErrorCauses limits the number of causes reported by DescribeError.
+const maxErrorCauses = 32
+
+// DescribeError returns the message, concrete type, causes and fields of err.
+// Causes are found with the Unwrap() error and Unwrap() []error methods used by
+// errors.Unwrap and errors.Join. Errors implementing ErrorDescriber describe themselves.
+// A nil err, such as a nil *T, has the message "<nil>" and nil causes are skipped.
+func DescribeError(err error) ErrorDetails {
+	if isNilError(err) {
+		return ErrorDetails{Message: "<nil>", Type: fmt.Sprintf("%T", err)}
+	}
+	if e, ok := err.(ErrorDescriber); ok {
+		return e.DescribeError()
+	}
+	d := ErrorDetails{Message: err.Error(), Type: fmt.Sprintf("%T", err)}
+	if f, ok := err.(ErrorFielder); ok {
+		d.Fields = append(d.Fields, f.LogFields()...)
+	}
+
+	queue := unwrapError(err)
+	for len(queue) > 0 && len(d.Causes) < maxErrorCauses {
+		cause := queue[0]
+		next := unwrapError(cause)
+		queue = append(append(make([]error, 0, len(next)+len(queue)-1), next...), queue[1:]...)
+		if isNilError(cause) {
+			continue
+		}
+		d.Causes = append(d.Causes, ErrorCause{Message: cause.Error(), Type: fmt.Sprintf("%T", cause)})
+		if f, ok := cause.(ErrorFielder); ok {
+			d.Fields = append(d.Fields, f.LogFields()...)
+		}
+	}
+	return d
+}
+
+func unwrapError(err error) []error {
+	switch v := err.(type) {
+	case interface{ Unwrap() error }:
+		if cause := v.Unwrap(); cause != nil {
+			return []error{cause}
+		}
+	case interface{ Unwrap() []error }:
+		return v.Unwrap()
+	}
+	return nil
+}
+
+// isNilError reports whether err is nil or holds a nil pointer, map, slice, func or
+// channel, such as a (*T)(nil) returned as an error, whose Error method may panic.
+func isNilError(err error) bool {
+	if err == nil {
+		return true
+	}
+	switch v := reflect.ValueOf(err); v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		return v.IsNil()
+	}
+	return false
+}
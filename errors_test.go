@@ -0,0 +1,88 @@
+package mo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type fieldsError struct {
+	err error
+}
+
+func (e fieldsError) Error() string      { return "query: " + e.err.Error() }
+func (e fieldsError) Unwrap() error      { return e.err }
+func (e fieldsError) LogFields() []Field { return []Field{Int("code", 42)} }
+
+type joinedError []error
+
+func (e joinedError) Error() string   { return "joined" }
+func (e joinedError) Unwrap() []error { return e }
+
+func TestDescribeError(t *testing.T) {
+	root := errors.New("timeout")
+	err := fmt.Errorf("load: %w", joinedError{fieldsError{root}, errors.New("retry")})
+
+	d := DescribeError(err)
+	if d.Message != err.Error() || d.Type != "*fmt.wrapError" {
+		t.Fatalf("unexpected error %q of type %s", d.Message, d.Type)
+	}
+
+	want := []string{"joined", "query: timeout", "timeout", "retry"}
+	if len(d.Causes) != len(want) {
+		t.Fatalf("got %d causes, want %d: %v", len(d.Causes), len(want), d.Causes)
+	}
+	for i, c := range d.Causes {
+		if c.Message != want[i] {
+			t.Errorf("cause %d = %q, want %q", i, c.Message, want[i])
+		}
+	}
+	if d.Causes[1].Type != "mo.fieldsError" {
+		t.Errorf("cause type = %s", d.Causes[1].Type)
+	}
+
+	if len(d.Fields) != 1 || d.Fields[0].Key() != "code" || d.Fields[0].Int64() != 42 {
+		t.Errorf("unexpected fields %v", d.Fields)
+	}
+}
+
+type describedError struct{}
+
+func (describedError) Error() string { return "described" }
+func (describedError) DescribeError() ErrorDetails {
+	return ErrorDetails{Message: "described", Type: "custom", Causes: []ErrorCause{{Message: "cause", Type: "custom"}}}
+}
+
+func TestDescribeError_Describer(t *testing.T) {
+	d := DescribeError(describedError{})
+	if d.Type != "custom" || len(d.Causes) != 1 || d.Causes[0].Message != "cause" {
+		t.Errorf("DescribeError() = %+v, want the details of the error", d)
+	}
+}
+
+type ptrError struct{ msg string }
+
+func (e *ptrError) Error() string { return e.msg }
+
+func TestDescribeError_TypedNil(t *testing.T) {
+	var err error = (*ptrError)(nil)
+	for _, f := range []Field{Err(err), Any("k", err)} {
+		if f.Type() != AnyType || f.Value() != nil {
+			t.Errorf("field of a nil *ptrError = type %d value %#v, want a nil AnyType field", f.Type(), f.Value())
+		}
+	}
+
+	if d := DescribeError(err); d.Message != "<nil>" || d.Type != "*mo.ptrError" {
+		t.Errorf("DescribeError() = %+v", d)
+	}
+	if d := DescribeError(fmt.Errorf("load: %w", err)); d.Message != "load: <nil>" || len(d.Causes) != 0 {
+		t.Errorf("DescribeError() = %+v, want no causes", d)
+	}
+
+	var buf bytes.Buffer
+	TextFormat{}.Write(&buf, Entry{Level: LevelError, Message: "failed", Fields: []Field{Err(err)}})
+	if got := buf.String(); got != "[ERR] failed error=<nil>\n" {
+		t.Errorf("got %q", got)
+	}
+}
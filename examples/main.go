@@ -28,9 +28,9 @@ func main() {
 	})
 	mo.SetBase(
 		mo.Value("ts", mo.Timestamp("15:04:05.000")),
-		mo.Value("caller", mo.Caller(3)),
 		mo.Value("trace.id", TraceId),
 	)
+	mo.SetCaller(true)
 
 	ctx := context.Background()
 	ctx = context.WithValue(ctx, requestIDKey, "req-00a8c6cbfb48928d391aab5ef3676fe3417176c9")
@@ -88,13 +88,19 @@ func main() {
 	mo.With(ctx2).Error("error message")
 	// mo.With(ctx2).Fatal("fatal message")
 
+	// Fields carried by the context are added to every message logged with it
+	ctx3 := mo.ContextWithFields(ctx2, mo.String("tenant", "acme"), mo.String("user.id", "u-1001"))
+	mo.Infox(ctx3, "info message with context fields")
+	mo.With(ctx3).Warnw("warnw message with context fields", fields...)
+
 	// Helper instance
-	log := mo.New(context.Background(), mo.NewLogger(
+	logger := mo.NewLogger(
 		mo.DefaultRecorder,
-		mo.Value("caller", mo.DefaultCaller),
 		mo.Value("ts", mo.Timestamp("15:04:05.000")),
 		mo.Value("trace.id", TraceId),
-	))
+	)
+	logger.SetCaller(true)
+	log := mo.New(context.Background(), logger)
 
 	// Helper methods - Debug
 	log.Debug("debug message")
@@ -151,6 +151,7 @@ func (s *mockSpan) SpanContext() trace.SpanContext {
 type LoggerOpts struct {
 	Filename   string `mapstructure:"filename"`           // log file path
 	Level      string `mapstructure:"level"`              // debug, info, warn, error, fatal
+	Console    string `mapstructure:"console.level"`      // level of console messages, defaults to Level
 	Timestamp  string `mapstructure:"timestamp"`          // time.TimeOnly, time.RFC3339, time.Unix
 	MaxSize    int    `mapstructure:"rolling.maxsize"`    // MB
 	MaxBackups int    `mapstructure:"rolling.maxbackups"` // number of backups
@@ -179,21 +180,36 @@ func Init(ctx context.Context, opts *LoggerOpts) *mo.Helper {
 	}
 
 	jsonRecorder := &record.JSON{
-		Encoder: json.NewEncoder(out),
+		Output: out,
 	}
 
-	recorder := mo.Combine(consoleRecorder, jsonRecorder)
+	level, err := mo.ParseLevelStrict(opts.Level)
+	if err != nil {
+		panic(err)
+	}
+	consoleLevel := level
+	if opts.Console != "" {
+		if consoleLevel, err = mo.ParseLevelStrict(opts.Console); err != nil {
+			panic(err)
+		}
+	}
+	recorder := record.CombineLevels(
+		record.Leveled{Level: consoleLevel, Recorder: consoleRecorder},
+		record.Leveled{Level: level, Recorder: jsonRecorder},
+	)
 	// id, _ := os.Hostname()
 	base := []mo.Field{
 		mo.Value("ts", mo.Timestamp(opts.Timestamp)),
-		mo.Value("caller", mo.Caller(3)),
 		// mo.Value("service.id", id),
 		mo.Value("trace.id", TraceID()),
 		// mo.Value("span.id", SpanID()),
 	}
 
 	log := mo.NewLogger(recorder, base...)
-	level := mo.ParseLevel(opts.Level)
+	log.SetCaller(true)
+	if consoleLevel < level {
+		level = consoleLevel
+	}
 	log.SetLevel(level)
 
 	return mo.New(ctx, log)
@@ -214,6 +230,7 @@ func main() {
 	log := Init(ctx, &LoggerOpts{
 		Filename:   "logs/app.log",
 		Level:      "debug",
+		Console:    "info",
 		Timestamp:  time.DateTime,
 		MaxSize:    10,
 		MaxBackups: 5,
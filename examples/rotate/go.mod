@@ -6,3 +6,9 @@ replace github.com/mengdu/mo => ../../
 
 require gopkg.in/natefinch/lumberjack.v2 v2.2.1 // indirect
 require github.com/mengdu/mo v0.5.1
+
+require (
+	github.com/mattn/go-isatty v0.0.20 // indirect
+	github.com/mengdu/color v0.4.0 // indirect
+	golang.org/x/sys v0.21.0 // indirect
+)
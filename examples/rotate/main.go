@@ -2,32 +2,14 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"os"
 	"path/filepath"
-	"strings"
 
 	"github.com/mengdu/mo"
+	"github.com/mengdu/mo/record"
 	"gopkg.in/natefinch/lumberjack.v2"
 )
 
-type JSONLogger struct {
-	encoder *json.Encoder
-}
-
-func (l *JSONLogger) Log(ctx context.Context, level mo.Level, msg string, kv []mo.Field) {
-	line := map[string]interface{}{
-		"level": strings.ToLower(level.String()),
-		"msg":   msg,
-	}
-
-	for _, v := range kv {
-		line[v.Key()] = v.Value()
-	}
-
-	l.encoder.Encode(line)
-}
-
 func main() {
 	filename := "./logs/app.log"
 	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
@@ -42,16 +24,14 @@ func main() {
 		LocalTime:  false,
 	}
 
-	logger := &JSONLogger{
-		encoder: json.NewEncoder(out),
-	}
-
-	mo.SetRecorder(logger)
+	mo.SetRecorder(&record.JSON{Output: out})
+	// Flush and close the log file on exit.
+	defer mo.Close(context.Background())
 	mo.SetBase(
 		mo.Value("ts", mo.Timestamp("15:04:05.000")),
-		mo.Value("caller", mo.Caller(3)),
 		mo.Value("tag", "dev"),
 	)
+	mo.SetCaller(true)
 
 	for i := 0; i < 1000; i++ {
 		demo()
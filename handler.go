@@ -0,0 +1,137 @@
+package mo
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LevelHandler returns an http.Handler that reports and changes the level of the logger.
+//
+// GET responds with the level of the logger and its effective level as JSON, such as
+// {"level":"info","effective":"info"}. The effective level differs when a prefix of the
+// logger's name is set in its Levels, which masks changes of the level, see Logger.EffectiveLevel.
+// PUT and POST change the level. The new level is read from a JSON body such as
+// {"level":"debug","ttl":"5m"}, or from the "level" and "ttl" form values.
+// When ttl is given, the previous level is restored after that duration unless
+// the level has been changed again in the meantime. A request made while a restore
+// is pending replaces it, and the level before the first temporary change is restored.
+func LevelHandler(logger *Logger) http.Handler {
+	return &levelHandler{logger: logger}
+}
+
+type levelHandler struct {
+	logger *Logger
+	mu     sync.Mutex  // Serializes changes and guards timer and prev
+	timer  *time.Timer // Pending restore of prev
+	prev   Level       // Level restored by timer
+}
+
+type levelPayload struct {
+	Level     string `json:"level,omitempty"`
+	Effective string `json:"effective,omitempty"`
+	TTL       string `json:"ttl,omitempty"`
+	Expires   string `json:"expires,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+func (h *levelHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.write(w, http.StatusOK, h.levels())
+	case http.MethodPut, http.MethodPost:
+		req, err := readLevelPayload(r)
+		if err != nil {
+			h.write(w, http.StatusBadRequest, levelPayload{Error: err.Error()})
+			return
+		}
+		level, err := ParseLevelStrict(req.Level)
+		if err != nil {
+			h.write(w, http.StatusBadRequest, levelPayload{Error: err.Error()})
+			return
+		}
+		var ttl time.Duration
+		if req.TTL != "" {
+			if ttl, err = time.ParseDuration(req.TTL); err != nil || ttl <= 0 {
+				h.write(w, http.StatusBadRequest, levelPayload{Error: fmt.Sprintf("invalid ttl %q", req.TTL)})
+				return
+			}
+		}
+		h.write(w, http.StatusOK, h.set(level, ttl))
+	default:
+		w.Header().Set("Allow", "GET, PUT, POST")
+		h.write(w, http.StatusMethodNotAllowed, levelPayload{Error: "method not allowed"})
+	}
+}
+
+// set changes the level and schedules the restore of the previous one when ttl > 0.
+func (h *levelHandler) set(level Level, ttl time.Duration) levelPayload {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// Restore the level from before the pending temporary change, not the temporary one.
+	prev := h.logger.Level()
+	if h.timer != nil {
+		h.timer.Stop()
+		h.timer = nil
+		prev = h.prev
+	}
+	h.logger.SetLevel(level)
+
+	res := h.levels()
+	if ttl > 0 {
+		var timer *time.Timer
+		timer = time.AfterFunc(ttl, func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			if h.timer != timer {
+				return
+			}
+			h.timer = nil
+			if h.logger.Level() == level {
+				h.logger.SetLevel(prev)
+			}
+		})
+		h.timer = timer
+		h.prev = prev
+		res.TTL = ttl.String()
+		res.Expires = time.Now().Add(ttl).Format(time.RFC3339)
+	}
+	return res
+}
+
+// levels returns the payload reporting the level and the effective level of the logger.
+func (h *levelHandler) levels() levelPayload {
+	return levelPayload{
+		Level:     levelName(h.logger.Level()),
+		Effective: levelName(h.logger.EffectiveLevel()),
+	}
+}
+
+func (h *levelHandler) write(w http.ResponseWriter, code int, payload levelPayload) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(payload)
+}
+
+func readLevelPayload(r *http.Request) (levelPayload, error) {
+	var req levelPayload
+	ct, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if ct == "application/json" {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return req, fmt.Errorf("invalid request body: %v", err)
+		}
+		return req, nil
+	}
+	req.Level = r.FormValue("level")
+	req.TTL = r.FormValue("ttl")
+	return req, nil
+}
+
+func levelName(level Level) string {
+	return strings.ToLower(level.String())
+}
@@ -0,0 +1,156 @@
+package mo
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func serveLevel(t *testing.T, h http.Handler, method, contentType, body string) (int, levelPayload) {
+	t.Helper()
+	req := httptest.NewRequest(method, "/log/level", strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	var res levelPayload
+	if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+		t.Fatalf("%s: invalid response %q: %v", method, rec.Body.String(), err)
+	}
+	return rec.Code, res
+}
+
+// waitLevel waits until the level of logger is level.
+func waitLevel(t *testing.T, logger *Logger, level Level) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for logger.Level() != level {
+		if time.Now().After(deadline) {
+			t.Fatalf("level %s not restored to %s", logger.Level(), level)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestLevelHandler(t *testing.T) {
+	logger := NewLogger(nil)
+	logger.SetLevel(LevelInfo)
+	h := LevelHandler(logger)
+
+	if code, res := serveLevel(t, h, http.MethodGet, "", ""); code != http.StatusOK || res.Level != "info" || res.Effective != "info" {
+		t.Errorf("GET = %d %+v", code, res)
+	}
+
+	tests := []struct {
+		method      string
+		contentType string
+		body        string
+		level       Level
+	}{
+		{http.MethodPut, "application/json", `{"level":"debug"}`, LevelDebug},
+		{http.MethodPost, "application/json; charset=utf-8", `{"level":"WARN"}`, LevelWarn},
+		{http.MethodPut, "application/x-www-form-urlencoded", url.Values{"level": {"error"}}.Encode(), LevelError},
+		{http.MethodPost, "application/x-www-form-urlencoded", "level=info", LevelInfo},
+	}
+	for _, tt := range tests {
+		code, res := serveLevel(t, h, tt.method, tt.contentType, tt.body)
+		if code != http.StatusOK || res.Level != levelName(tt.level) || logger.Level() != tt.level {
+			t.Errorf("%s %s = %d %+v, logger level %s", tt.method, tt.body, code, res, logger.Level())
+		}
+	}
+
+	bad := []struct {
+		contentType string
+		body        string
+	}{
+		{"application/json", `{"level":"verbose"}`},
+		{"application/json", `{"level":`},
+		{"application/json", `{"level":"debug","ttl":"soon"}`},
+		{"application/json", `{"level":"debug","ttl":"-1m"}`},
+		{"application/x-www-form-urlencoded", "level=debug&ttl=0s"},
+		{"application/x-www-form-urlencoded", ""},
+	}
+	for _, tt := range bad {
+		code, res := serveLevel(t, h, http.MethodPut, tt.contentType, tt.body)
+		if code != http.StatusBadRequest || res.Error == "" {
+			t.Errorf("PUT %s = %d %+v, want 400", tt.body, code, res)
+		}
+	}
+	if logger.Level() != LevelInfo {
+		t.Errorf("bad requests changed the level to %s", logger.Level())
+	}
+
+	if code, _ := serveLevel(t, h, http.MethodDelete, "", ""); code != http.StatusMethodNotAllowed {
+		t.Errorf("DELETE = %d, want 405", code)
+	}
+}
+
+func TestLevelHandler_EffectiveLevel(t *testing.T) {
+	logger := NewLogger(nil).Named("app")
+	logger.SetLevel(LevelInfo)
+	levels, _ := NewLevels("app=error")
+	logger.SetLevels(levels)
+	h := LevelHandler(logger)
+	if _, res := serveLevel(t, h, http.MethodGet, "", ""); res.Level != "info" || res.Effective != "error" {
+		t.Errorf("GET = %+v, want the level and the level of the name prefix", res)
+	}
+
+	// A change masked by the prefix is reported as such.
+	code, res := serveLevel(t, h, http.MethodPut, "application/json", `{"level":"debug"}`)
+	if code != http.StatusOK || res.Level != "debug" || res.Effective != "error" {
+		t.Errorf("PUT = %d %+v, want the masked level", code, res)
+	}
+
+	levels.Delete("app")
+	if _, res := serveLevel(t, h, http.MethodGet, "", ""); res.Level != "debug" || res.Effective != "debug" {
+		t.Errorf("GET = %+v after removing the prefix", res)
+	}
+}
+
+func TestLevelHandler_TTL(t *testing.T) {
+	logger := NewLogger(nil)
+	logger.SetLevel(LevelInfo)
+	h := LevelHandler(logger)
+
+	code, res := serveLevel(t, h, http.MethodPut, "application/json", `{"level":"debug","ttl":"20ms"}`)
+	if code != http.StatusOK || res.TTL != "20ms" || res.Expires == "" || logger.Level() != LevelDebug {
+		t.Fatalf("PUT = %d %+v", code, res)
+	}
+	waitLevel(t, logger, LevelInfo)
+
+	// A level changed in the meantime is not overwritten by the restore.
+	serveLevel(t, h, http.MethodPut, "application/json", `{"level":"debug","ttl":"20ms"}`)
+	logger.SetLevel(LevelWarn)
+	time.Sleep(50 * time.Millisecond)
+	if logger.Level() != LevelWarn {
+		t.Errorf("restore overwrote the level %s", logger.Level())
+	}
+}
+
+func TestLevelHandler_OverlappingTTL(t *testing.T) {
+	logger := NewLogger(nil)
+	logger.SetLevel(LevelInfo)
+	h := LevelHandler(logger)
+
+	serveLevel(t, h, http.MethodPut, "application/json", `{"level":"debug","ttl":"1h"}`)
+	serveLevel(t, h, http.MethodPut, "application/json", `{"level":"warn","ttl":"20ms"}`)
+	if logger.Level() != LevelWarn {
+		t.Fatalf("level %s, want warn", logger.Level())
+	}
+	// The level from before the first temporary change is restored.
+	waitLevel(t, logger, LevelInfo)
+
+	// A permanent change cancels the pending restore.
+	serveLevel(t, h, http.MethodPut, "application/json", `{"level":"debug","ttl":"20ms"}`)
+	serveLevel(t, h, http.MethodPut, "application/json", `{"level":"error"}`)
+	time.Sleep(50 * time.Millisecond)
+	if logger.Level() != LevelError {
+		t.Errorf("level %s, want error", logger.Level())
+	}
+}
@@ -2,7 +2,6 @@ package mo
 
 import (
 	"context"
-	"os"
 )
 
 // Helper is a helper struct for logging.
@@ -21,158 +20,244 @@ func (h Helper) With(ctx context.Context) *Helper {
 	return &Helper{ctx: ctx, Logger: h.Logger}
 }
 
+// WithFields returns a new Helper whose Logger adds the given key-value pairs to all log messages.
+func (h Helper) WithFields(kv ...Field) *Helper {
+	return &Helper{ctx: h.ctx, Logger: h.Logger.With(kv...)}
+}
+
+// Named returns a new Helper whose Logger is named by appending name to the current logger name.
+func (h Helper) Named(name string) *Helper {
+	return &Helper{ctx: h.ctx, Logger: h.Logger.Named(name)}
+}
+
+// AddCallerSkip returns a new Helper whose Logger skips n additional stack frames when
+// capturing the caller, for use by functions that wrap the Helper methods.
+func (h Helper) AddCallerSkip(n int) *Helper {
+	return &Helper{ctx: h.ctx, Logger: h.Logger.AddCallerSkip(n)}
+}
+
+// Log logs a message at the given level.
+func (h Helper) Log(level Level, a ...interface{}) {
+	h.Logger.print(h.ctx, level, a, nil)
+}
+
+// Logf logs a formatted message at the given level.
+func (h Helper) Logf(level Level, format string, a ...interface{}) {
+	h.Logger.printf(h.ctx, level, format, a, nil)
+}
+
+// Logw logs a message with key-value pairs at the given level.
+func (h Helper) Logw(level Level, msg string, kv ...Field) {
+	h.Logger.printw(h.ctx, level, msg, kv)
+}
+
+// Logx logs a message at the given level with the given context.
+func (h Helper) Logx(ctx context.Context, level Level, a ...interface{}) {
+	h.Logger.print(ctx, level, a, nil)
+}
+
+// Logfx logs a formatted message at the given level with the given context.
+func (h Helper) Logfx(ctx context.Context, level Level, format string, a ...interface{}) {
+	h.Logger.printf(ctx, level, format, a, nil)
+}
+
+// Logwx logs a message with key-value pairs at the given level with the given context.
+func (h Helper) Logwx(ctx context.Context, level Level, msg string, kv ...Field) {
+	h.Logger.printw(ctx, level, msg, kv)
+}
+
 // Debug logs a message at the debug level.
 func (h Helper) Debug(a ...interface{}) {
-	h.Logger.Print(h.ctx, LevelDebug, a...)
+	h.Logger.print(h.ctx, LevelDebug, a, nil)
 }
 
 // Debugf logs a formatted message at the debug level.
 func (h Helper) Debugf(format string, a ...interface{}) {
-	h.Logger.Printf(h.ctx, LevelDebug, format, a...)
+	h.Logger.printf(h.ctx, LevelDebug, format, a, nil)
 }
 
 // Debugw logs a message with key-value pairs at the debug level.
 func (h Helper) Debugw(msg string, kv ...Field) {
-	h.Logger.Printw(h.ctx, LevelDebug, msg, kv...)
+	h.Logger.printw(h.ctx, LevelDebug, msg, kv)
 }
 
 // Debugx logs a message at the debug level with the given context.
 func (h Helper) Debugx(ctx context.Context, a ...interface{}) {
-	h.Logger.Print(ctx, LevelDebug, a...)
+	h.Logger.print(ctx, LevelDebug, a, nil)
 }
 
 // Debugfx logs a formatted message at the debug level with the given context.
 func (h Helper) Debugfx(ctx context.Context, format string, a ...interface{}) {
-	h.Logger.Printf(ctx, LevelDebug, format, a...)
+	h.Logger.printf(ctx, LevelDebug, format, a, nil)
 }
 
 // Debugwx logs a message with key-value pairs at the debug level with the given context.
 func (h Helper) Debugwx(ctx context.Context, msg string, kv ...Field) {
-	h.Logger.Printw(ctx, LevelDebug, msg, kv...)
+	h.Logger.printw(ctx, LevelDebug, msg, kv)
 }
 
 // Info logs a message at the info level.
 func (h Helper) Info(a ...interface{}) {
-	h.Logger.Print(h.ctx, LevelInfo, a...)
+	h.Logger.print(h.ctx, LevelInfo, a, nil)
 }
 
 // Infof logs a formatted message at the info level.
 func (h Helper) Infof(format string, a ...interface{}) {
-	h.Logger.Printf(h.ctx, LevelInfo, format, a...)
+	h.Logger.printf(h.ctx, LevelInfo, format, a, nil)
 }
 
 // Infow logs a message with key-value pairs at the info level.
 func (h Helper) Infow(msg string, kv ...Field) {
-	h.Logger.Printw(h.ctx, LevelInfo, msg, kv...)
+	h.Logger.printw(h.ctx, LevelInfo, msg, kv)
 }
 
 // Infox logs a message at the info level with the given context.
 func (h Helper) Infox(ctx context.Context, a ...interface{}) {
-	h.Logger.Print(ctx, LevelInfo, a...)
+	h.Logger.print(ctx, LevelInfo, a, nil)
 }
 
 // Infofx logs a formatted message at the info level with the given context.
 func (h Helper) Infofx(ctx context.Context, format string, a ...interface{}) {
-	h.Logger.Printf(ctx, LevelInfo, format, a...)
+	h.Logger.printf(ctx, LevelInfo, format, a, nil)
 }
 
 // Infowx logs a message with key-value pairs at the info level with the given context.
 func (h Helper) Infowx(ctx context.Context, msg string, kv ...Field) {
-	h.Logger.Printw(ctx, LevelInfo, msg, kv...)
+	h.Logger.printw(ctx, LevelInfo, msg, kv)
 }
 
 // Warn logs a message at the warn level.
 func (h Helper) Warn(a ...interface{}) {
-	h.Logger.Print(h.ctx, LevelWarn, a...)
+	h.Logger.print(h.ctx, LevelWarn, a, nil)
 }
 
 // Warnf logs a formatted message at the warn level.
 func (h Helper) Warnf(format string, a ...interface{}) {
-	h.Logger.Printf(h.ctx, LevelWarn, format, a...)
+	h.Logger.printf(h.ctx, LevelWarn, format, a, nil)
 }
 
 // Warnw logs a message with key-value pairs at the warn level.
 func (h Helper) Warnw(msg string, kv ...Field) {
-	h.Logger.Printw(h.ctx, LevelWarn, msg, kv...)
+	h.Logger.printw(h.ctx, LevelWarn, msg, kv)
 }
 
 // Warnx logs a message at the warn level with the given context.
 func (h Helper) Warnx(ctx context.Context, a ...interface{}) {
-	h.Logger.Print(ctx, LevelWarn, a...)
+	h.Logger.print(ctx, LevelWarn, a, nil)
 }
 
 // Warnfx logs a formatted message at the warn level with the given context.
 func (h Helper) Warnfx(ctx context.Context, format string, a ...interface{}) {
-	h.Logger.Printf(ctx, LevelWarn, format, a...)
+	h.Logger.printf(ctx, LevelWarn, format, a, nil)
 }
 
 // Warnwx logs a message with key-value pairs at the warn level with the given context.
 func (h Helper) Warnwx(ctx context.Context, msg string, kv ...Field) {
-	h.Logger.Printw(ctx, LevelWarn, msg, kv...)
+	h.Logger.printw(ctx, LevelWarn, msg, kv)
 }
 
 // Error logs a message at the error level.
 func (h Helper) Error(a ...interface{}) {
-	h.Logger.Print(h.ctx, LevelError, a...)
+	h.Logger.print(h.ctx, LevelError, a, nil)
 }
 
 // Errorf logs a formatted message at the error level.
 func (h Helper) Errorf(format string, a ...interface{}) {
-	h.Logger.Printf(h.ctx, LevelError, format, a...)
+	h.Logger.printf(h.ctx, LevelError, format, a, nil)
 }
 
 // Errorw logs a message with key-value pairs at the error level.
 func (h Helper) Errorw(msg string, kv ...Field) {
-	h.Logger.Printw(h.ctx, LevelError, msg, kv...)
+	h.Logger.printw(h.ctx, LevelError, msg, kv)
 }
 
 // Errorx logs a message at the error level with the given context.
 func (h Helper) Errorx(ctx context.Context, a ...interface{}) {
-	h.Logger.Print(ctx, LevelError, a...)
+	h.Logger.print(ctx, LevelError, a, nil)
 }
 
 // Errorfx logs a formatted message at the error level with the given context.
 func (h Helper) Errorfx(ctx context.Context, format string, a ...interface{}) {
-	h.Logger.Printf(ctx, LevelError, format, a...)
+	h.Logger.printf(ctx, LevelError, format, a, nil)
 }
 
 // Errorwx logs a message with key-value pairs at the error level with the given context.
 func (h Helper) Errorwx(ctx context.Context, msg string, kv ...Field) {
-	h.Logger.Printw(ctx, LevelError, msg, kv...)
+	h.Logger.printw(ctx, LevelError, msg, kv)
+}
+
+// Panic logs a message at the panic level and panics.
+func (h Helper) Panic(a ...interface{}) {
+	msg := sprint(a...)
+	h.Logger.printw(h.ctx, LevelPanic, msg, nil)
+	panic(msg)
+}
+
+// Panicf logs a formatted message at the panic level and panics.
+func (h Helper) Panicf(format string, a ...interface{}) {
+	msg := sprintf(format, a...)
+	h.Logger.printw(h.ctx, LevelPanic, msg, nil)
+	panic(msg)
+}
+
+// Panicw logs a message with key-value pairs at the panic level and panics.
+func (h Helper) Panicw(msg string, kv ...Field) {
+	h.Logger.printw(h.ctx, LevelPanic, msg, kv)
+	panic(msg)
+}
+
+// Panicx logs a message at the panic level with the given context and panics.
+func (h Helper) Panicx(ctx context.Context, a ...interface{}) {
+	msg := sprint(a...)
+	h.Logger.printw(ctx, LevelPanic, msg, nil)
+	panic(msg)
+}
+
+// Panicfx logs a formatted message at the panic level with the given context and panics.
+func (h Helper) Panicfx(ctx context.Context, format string, a ...interface{}) {
+	msg := sprintf(format, a...)
+	h.Logger.printw(ctx, LevelPanic, msg, nil)
+	panic(msg)
+}
+
+// Panicwx logs a message with key-value pairs at the panic level with the given context and panics.
+func (h Helper) Panicwx(ctx context.Context, msg string, kv ...Field) {
+	h.Logger.printw(ctx, LevelPanic, msg, kv)
+	panic(msg)
 }
 
 // Fatal logs a message at the fatal level and exits the program.
 func (h Helper) Fatal(a ...interface{}) {
-	h.Logger.Print(h.ctx, LevelFatal, a...)
-	os.Exit(1)
+	h.Logger.print(h.ctx, LevelFatal, a, nil)
+	h.Logger.exit(1)
 }
 
 // Fatalf logs a formatted message at the fatal level and exits the program.
 func (h Helper) Fatalf(format string, a ...interface{}) {
-	h.Logger.Printf(h.ctx, LevelFatal, format, a...)
-	os.Exit(1)
+	h.Logger.printf(h.ctx, LevelFatal, format, a, nil)
+	h.Logger.exit(1)
 }
 
 // Fatalw logs a message with key-value pairs at the fatal level and exits the program.
 func (h Helper) Fatalw(msg string, kv ...Field) {
-	h.Logger.Printw(h.ctx, LevelFatal, msg, kv...)
-	os.Exit(1)
+	h.Logger.printw(h.ctx, LevelFatal, msg, kv)
+	h.Logger.exit(1)
 }
 
 // Fatalx logs a message at the fatal level with the given context and exits the program.
 func (h Helper) Fatalx(ctx context.Context, a ...interface{}) {
-	h.Logger.Print(ctx, LevelFatal, a...)
-	os.Exit(1)
+	h.Logger.print(ctx, LevelFatal, a, nil)
+	h.Logger.exit(1)
 }
 
 // Fatalfx logs a formatted message at the fatal level with the given context and exits the program.
 func (h Helper) Fatalfx(ctx context.Context, format string, a ...interface{}) {
-	h.Logger.Printf(ctx, LevelFatal, format, a...)
-	os.Exit(1)
+	h.Logger.printf(ctx, LevelFatal, format, a, nil)
+	h.Logger.exit(1)
 }
 
 // Fatalwx logs a message with key-value pairs at the fatal level with the given context and exits the program.
 func (h Helper) Fatalwx(ctx context.Context, msg string, kv ...Field) {
-	h.Logger.Printw(ctx, LevelFatal, msg, kv...)
-	os.Exit(1)
+	h.Logger.printw(ctx, LevelFatal, msg, kv)
+	h.Logger.exit(1)
 }
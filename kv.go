@@ -3,6 +3,7 @@ package mo
 import (
 	"context"
 	"fmt"
+	"math"
 	"os"
 	"runtime"
 	"strconv"
@@ -10,42 +11,388 @@ import (
 	"time"
 )
 
-type Field [2]interface{}
+// FieldType is the type of the value held by a Field.
+type FieldType uint8
 
+const (
+	// AnyType is a field holding an arbitrary value, see Any.
+	AnyType FieldType = iota
+	// StringType is a field holding a string.
+	StringType
+	// Int64Type is a field holding a signed integer.
+	Int64Type
+	// Uint64Type is a field holding an unsigned integer.
+	Uint64Type
+	// Float64Type is a field holding a float64.
+	Float64Type
+	// Float32Type is a field holding a float32.
+	Float32Type
+	// BoolType is a field holding a bool.
+	BoolType
+	// DurationType is a field holding a time.Duration.
+	DurationType
+	// TimeType is a field holding a time.Time.
+	TimeType
+	// ErrorType is a field holding a non-nil error.
+	ErrorType
+	// CallerType is a field holding the program counter of a call site, see Logger.SetCaller.
+	CallerType
+	// StackType is a field holding a Stacktrace, see Stack.
+	StackType
+)
+
+// Field is a key-value pair attached to a log message.
+//
+// Fields created by the typed constructors such as String, Int64 or Bool store
+// their values without boxing them in an interface, so recorders can encode
+// them without allocations or reflection by switching on Type.
+type Field struct {
+	key   string
+	typ   FieldType
+	num   int64       // Integer, bits of floats, bool, duration and unix nanoseconds of times
+	str   string      // String value
+	iface interface{} // Arbitrary value, error, or location of times
+}
+
+// Key returns the key of the field.
 func (v Field) Key() string {
-	if v, ok := v[0].(string); ok {
-		return v
-	}
-	return fmt.Sprint(v[0])
+	return v.key
 }
 
+// Type returns the type of the value held by the field.
+func (v Field) Type() FieldType {
+	return v.typ
+}
+
+// Value returns the value of the field. Values of typed fields are boxed in an interface.
 func (v Field) Value() interface{} {
-	return v[1]
+	switch v.typ {
+	case StringType:
+		return v.str
+	case Int64Type:
+		return v.num
+	case Uint64Type:
+		return uint64(v.num)
+	case Float64Type:
+		return v.Float64()
+	case Float32Type:
+		return float32(v.Float64())
+	case BoolType:
+		return v.num == 1
+	case DurationType:
+		return time.Duration(v.num)
+	case TimeType:
+		return v.Time()
+	case CallerType:
+		return string(v.AppendValue(nil))
+	default:
+		return v.iface
+	}
+}
+
+// Str returns the value of a StringType field.
+func (v Field) Str() string {
+	return v.str
+}
+
+// Int64 returns the value of an Int64Type field.
+func (v Field) Int64() int64 {
+	return v.num
+}
+
+// Uint64 returns the value of a Uint64Type field.
+func (v Field) Uint64() uint64 {
+	return uint64(v.num)
+}
+
+// Float64 returns the value of a Float64Type or Float32Type field.
+func (v Field) Float64() float64 {
+	return math.Float64frombits(uint64(v.num))
+}
+
+// Bool returns the value of a BoolType field.
+func (v Field) Bool() bool {
+	return v.num == 1
+}
+
+// Duration returns the value of a DurationType field.
+func (v Field) Duration() time.Duration {
+	return time.Duration(v.num)
+}
+
+// Time returns the value of a TimeType field.
+func (v Field) Time() time.Time {
+	if loc, ok := v.iface.(*time.Location); ok {
+		return time.Unix(0, v.num).In(loc)
+	}
+	return time.Unix(0, v.num)
+}
+
+// Frame returns the file, line and function of a CallerType field.
+func (v Field) Frame() runtime.Frame {
+	frame, _ := runtime.CallersFrames([]uintptr{uintptr(v.num)}).Next()
+	return frame
+}
+
+// Stacktrace returns the value of a StackType field.
+func (v Field) Stacktrace() Stacktrace {
+	st, _ := v.iface.(Stacktrace)
+	return st
+}
+
+// Err returns the value of an ErrorType field.
+func (v Field) Err() error {
+	err, _ := v.iface.(error)
+	return err
+}
+
+// Any returns the value of an AnyType field.
+func (v Field) Any() interface{} {
+	return v.iface
+}
+
+// AppendValue appends the textual representation of the field value to dst.
+// Typed fields are formatted without reflection; AnyType values use fmt.
+func (v Field) AppendValue(dst []byte) []byte {
+	switch v.typ {
+	case StringType:
+		return append(dst, v.str...)
+	case Int64Type:
+		return strconv.AppendInt(dst, v.num, 10)
+	case Uint64Type:
+		return strconv.AppendUint(dst, uint64(v.num), 10)
+	case Float64Type:
+		return strconv.AppendFloat(dst, v.Float64(), 'g', -1, 64)
+	case Float32Type:
+		return strconv.AppendFloat(dst, v.Float64(), 'g', -1, 32)
+	case BoolType:
+		return strconv.AppendBool(dst, v.num == 1)
+	case DurationType:
+		return append(dst, time.Duration(v.num).String()...)
+	case TimeType:
+		return v.Time().AppendFormat(dst, time.RFC3339Nano)
+	case ErrorType:
+		return append(dst, v.Err().Error()...)
+	case CallerType:
+		frame := v.Frame()
+		return appendCaller(dst, frame.File, frame.Line)
+	case StackType:
+		return append(dst, v.Stacktrace().String()...)
+	default:
+		return append(dst, fmt.Sprint(v.iface)...)
+	}
+}
+
+// Text returns the textual representation of the field value, see AppendValue.
+func (v Field) Text() string {
+	if v.typ == StringType {
+		return v.str
+	}
+	var tmp [64]byte
+	return string(v.AppendValue(tmp[:0]))
 }
 
+// valuer returns the Valuer held by an AnyType field.
+func (v Field) valuer() (Valuer, bool) {
+	if v.typ != AnyType {
+		return nil, false
+	}
+	fn, ok := v.iface.(Valuer)
+	return fn, ok
+}
+
+// Value returns a field for an arbitrary value, see Any.
 func Value(key string, value interface{}) Field {
-	return Field{key, value}
+	return Any(key, value)
+}
+
+// Any returns a field for an arbitrary value. Values of the types supported by the
+// typed constructors are stored as typed fields; other values, including Valuers
+// and LogValuers, are stored as AnyType.
+func Any(key string, value interface{}) Field {
+	switch v := value.(type) {
+	case string:
+		return String(key, v)
+	case int:
+		return Int64(key, int64(v))
+	case int64:
+		return Int64(key, v)
+	case int32:
+		return Int64(key, int64(v))
+	case int16:
+		return Int64(key, int64(v))
+	case int8:
+		return Int64(key, int64(v))
+	case uint:
+		return Uint64(key, uint64(v))
+	case uint64:
+		return Uint64(key, v)
+	case uint32:
+		return Uint64(key, uint64(v))
+	case uint16:
+		return Uint64(key, uint64(v))
+	case uint8:
+		return Uint64(key, uint64(v))
+	case float64:
+		return Float64(key, v)
+	case float32:
+		return Float32(key, v)
+	case bool:
+		return Bool(key, v)
+	case time.Duration:
+		return Duration(key, v)
+	case time.Time:
+		return Time(key, v)
+	case LogValuer:
+		return Field{key: key, typ: AnyType, iface: value}
+	case error:
+		return NamedErr(key, v)
+	default:
+		return Field{key: key, typ: AnyType, iface: value}
+	}
+}
+
+// String returns a field with a string value.
+func String(key string, value string) Field {
+	return Field{key: key, typ: StringType, str: value}
+}
+
+// Int returns a field with an int value.
+func Int(key string, value int) Field {
+	return Int64(key, int64(value))
+}
+
+// Int64 returns a field with an int64 value.
+func Int64(key string, value int64) Field {
+	return Field{key: key, typ: Int64Type, num: value}
+}
+
+// Uint64 returns a field with a uint64 value.
+func Uint64(key string, value uint64) Field {
+	return Field{key: key, typ: Uint64Type, num: int64(value)}
+}
+
+// Float64 returns a field with a float64 value.
+func Float64(key string, value float64) Field {
+	return Field{key: key, typ: Float64Type, num: int64(math.Float64bits(value))}
+}
+
+// Float32 returns a field with a float32 value.
+func Float32(key string, value float32) Field {
+	return Field{key: key, typ: Float32Type, num: int64(math.Float64bits(float64(value)))}
+}
+
+// Bool returns a field with a bool value.
+func Bool(key string, value bool) Field {
+	f := Field{key: key, typ: BoolType}
+	if value {
+		f.num = 1
+	}
+	return f
+}
+
+// Duration returns a field with a time.Duration value.
+func Duration(key string, value time.Duration) Field {
+	return Field{key: key, typ: DurationType, num: int64(value)}
+}
+
+var (
+	minTime = time.Unix(0, math.MinInt64)
+	maxTime = time.Unix(0, math.MaxInt64)
+)
+
+// Time returns a field with a time.Time value.
+// Times outside the range of UnixNano are stored as AnyType.
+func Time(key string, value time.Time) Field {
+	if value.Before(minTime) || value.After(maxTime) {
+		return Field{key: key, typ: AnyType, iface: value}
+	}
+	return Field{key: key, typ: TimeType, num: value.UnixNano(), iface: value.Location()}
+}
+
+// ErrorKey is the key of fields created by Err.
+const ErrorKey = "error"
+
+// Err returns a field with the ErrorKey key for err. A nil err gives a nil AnyType field.
+func Err(err error) Field {
+	return NamedErr(ErrorKey, err)
+}
+
+// NamedErr returns a field with the given key for err. A nil err, including a nil
+// pointer such as (*T)(nil) returned as an error, gives a nil AnyType field.
+func NamedErr(key string, err error) Field {
+	if isNilError(err) {
+		return Field{key: key, typ: AnyType}
+	}
+	return Field{key: key, typ: ErrorType, iface: err}
 }
 
 type Valuer func(ctx context.Context) interface{}
 
+// LogValuer is implemented by types that control their representation in log messages,
+// for example to hide secrets or to log a summary of a large value. Logger replaces
+// values implementing LogValuer with the result of LogValue, which may be of any type
+// accepted by Any, before the fields are passed to the recorder.
+type LogValuer interface {
+	LogValue() interface{}
+}
+
+// maxLogValueDepth limits the number of LogValue calls made to resolve a field,
+// in case LogValue keeps returning LogValuers.
+const maxLogValueDepth = 100
+
+// resolve returns v with a LogValuer value replaced by the result of its LogValue
+// method, repeatedly if the result is a LogValuer itself.
+func (v Field) resolve() Field {
+	for i := 0; i < maxLogValueDepth && v.typ == AnyType; i++ {
+		lv, ok := v.iface.(LogValuer)
+		if !ok {
+			return v
+		}
+		v = Any(v.key, logValue(lv))
+	}
+	return v
+}
+
+// logValue calls lv.LogValue, returning an error if it panics.
+func logValue(lv LogValuer) (value interface{}) {
+	defer func() {
+		if r := recover(); r != nil {
+			value = fmt.Errorf("LogValue panicked: %v", r)
+		}
+	}()
+	return lv.LogValue()
+}
+
+// CallerKey is the key of the caller field added by Logger.SetCaller.
+const CallerKey = "caller"
+
 var callerAbs, _ = strconv.ParseBool(os.Getenv("MO_CALLER_ABS"))
 
+// appendCaller appends "file:line" to dst, with file shortened to its parent
+// directory and name unless MO_CALLER_ABS is set.
+func appendCaller(dst []byte, file string, line int) []byte {
+	if !callerAbs {
+		if idx := strings.LastIndexByte(file, '/'); idx != -1 {
+			idx = strings.LastIndexByte(file[:idx], '/')
+			file = file[idx+1:]
+		}
+	}
+	dst = append(dst, file...)
+	dst = append(dst, ':')
+	return strconv.AppendInt(dst, int64(line), 10)
+}
+
+// Caller returns a Valuer resolving to the "file:line" of the caller skip frames up the stack.
+// Prefer Logger.SetCaller, which captures the caller once at the call site.
 func Caller(skip int) Valuer {
 	return func(ctx context.Context) interface{} {
 		_, file, line, _ := runtime.Caller(skip)
-		if callerAbs {
-			return file + ":" + strconv.Itoa(line)
-		}
-		idx := strings.LastIndexByte(file, '/')
-		if idx == -1 {
-			return file + ":" + strconv.Itoa(line)
-		}
-		idx = strings.LastIndexByte(file[:idx], '/')
-		return file[idx+1:] + ":" + strconv.Itoa(line)
+		return string(appendCaller(nil, file, line))
 	}
 }
 
+// DefaultCaller is a Valuer resolving to the caller of the logging methods and functions.
 var DefaultCaller = Caller(3)
 
 func Timestamp(layout string) Valuer {
@@ -0,0 +1,116 @@
+package mo
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestField_Typed(t *testing.T) {
+	loc := time.FixedZone("UTC+8", 8*3600)
+	ts := time.Date(2024, 10, 18, 9, 30, 0, 5, loc)
+	err := errors.New("boom")
+
+	tests := []struct {
+		field Field
+		typ   FieldType
+		value interface{}
+		text  string
+	}{
+		{String("k", "v"), StringType, "v", "v"},
+		{Int("k", -1), Int64Type, int64(-1), "-1"},
+		{Int64("k", math.MinInt64), Int64Type, int64(math.MinInt64), "-9223372036854775808"},
+		{Uint64("k", math.MaxUint64), Uint64Type, uint64(math.MaxUint64), "18446744073709551615"},
+		{Float64("k", 1.5), Float64Type, 1.5, "1.5"},
+		{Float32("k", 0.1), Float32Type, float32(0.1), "0.1"},
+		{Bool("k", true), BoolType, true, "true"},
+		{Bool("k", false), BoolType, false, "false"},
+		{Duration("k", 1500*time.Millisecond), DurationType, 1500 * time.Millisecond, "1.5s"},
+		{Time("k", ts), TimeType, ts, "2024-10-18T09:30:00.000000005+08:00"},
+		{NamedErr("k", err), ErrorType, err, "boom"},
+		{NamedErr("k", nil), AnyType, nil, "<nil>"},
+	}
+	for _, tt := range tests {
+		f := tt.field
+		if f.Key() != "k" || f.Type() != tt.typ {
+			t.Errorf("%s: key %q, type %d, want type %d", tt.text, f.Key(), f.Type(), tt.typ)
+		}
+		if v := f.Value(); v != tt.value {
+			if got, ok := v.(time.Time); !ok || !got.Equal(ts) || got.Location() != loc {
+				t.Errorf("%s: Value() = %#v, want %#v", tt.text, v, tt.value)
+			}
+		}
+		if got := string(f.AppendValue([]byte("x="))); got != "x="+tt.text {
+			t.Errorf("AppendValue() = %q, want %q", got, "x="+tt.text)
+		}
+	}
+
+	if Err(err).Key() != ErrorKey || Err(err).Err() != err {
+		t.Error("Err should use ErrorKey")
+	}
+}
+
+func TestAny(t *testing.T) {
+	type point struct{ X, Y int }
+	ts := time.Unix(1700000000, 0)
+	err := errors.New("boom")
+	var lv LogValuer = secret("s3cr3t")
+
+	tests := []struct {
+		value interface{}
+		typ   FieldType
+		want  interface{}
+	}{
+		{"v", StringType, "v"},
+		{int(-1), Int64Type, int64(-1)},
+		{int8(-8), Int64Type, int64(-8)},
+		{int16(-16), Int64Type, int64(-16)},
+		{int32(-32), Int64Type, int64(-32)},
+		{int64(-64), Int64Type, int64(-64)},
+		{uint(1), Uint64Type, uint64(1)},
+		{uint8(8), Uint64Type, uint64(8)},
+		{uint16(16), Uint64Type, uint64(16)},
+		{uint32(32), Uint64Type, uint64(32)},
+		{uint64(64), Uint64Type, uint64(64)},
+		{1.5, Float64Type, 1.5},
+		{float32(2.5), Float32Type, float32(2.5)},
+		{true, BoolType, true},
+		{time.Second, DurationType, time.Second},
+		{err, ErrorType, err},
+		{lv, AnyType, lv},
+		{point{1, 2}, AnyType, point{1, 2}},
+		{nil, AnyType, nil},
+	}
+	for _, tt := range tests {
+		f := Any("k", tt.value)
+		if f.Type() != tt.typ || f.Value() != tt.want {
+			t.Errorf("Any(%#v) = type %d value %#v, want type %d value %#v", tt.value, f.Type(), f.Value(), tt.typ, tt.want)
+		}
+		if Value("k", tt.value) != f {
+			t.Errorf("Value(%#v) differs from Any", tt.value)
+		}
+	}
+
+	if f := Any("k", ts); f.Type() != TimeType || !f.Time().Equal(ts) {
+		t.Errorf("Any(time) = type %d value %v", f.Type(), f.Value())
+	}
+}
+
+func TestTime_OutOfRange(t *testing.T) {
+	for _, ts := range []time.Time{
+		time.Date(1000, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(3000, 1, 1, 0, 0, 0, 0, time.UTC),
+	} {
+		f := Time("k", ts)
+		if f.Type() != AnyType {
+			t.Fatalf("Time(%v) has type %d, want AnyType", ts, f.Type())
+		}
+		if got, ok := f.Value().(time.Time); !ok || !got.Equal(ts) {
+			t.Errorf("Time(%v).Value() = %v", ts, f.Value())
+		}
+		if got := string(f.AppendValue(nil)); got != ts.String() {
+			t.Errorf("AppendValue() = %q, want %q", got, ts.String())
+		}
+	}
+}
@@ -1,24 +1,47 @@
 package mo
 
-import "strings"
+import (
+	"encoding"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
 
 // Level is a logger level.
 type Level int8
 
+// Ensure Level can be used in configs and command-line flags.
+var (
+	_ encoding.TextMarshaler   = Level(0)
+	_ encoding.TextUnmarshaler = (*Level)(nil)
+	_ json.Marshaler           = Level(0)
+	_ json.Unmarshaler         = (*Level)(nil)
+	_ flag.Value               = (*Level)(nil)
+	_ flag.Value               = (*AtomicLevel)(nil)
+)
+
 // LevelKey is logger level key.
 const LevelKey = "level"
 
+// The built-in levels are spaced apart so that custom levels registered with
+// RegisterLevel can be placed between them.
 const (
 	// LevelDebug is logger debug level.
-	LevelDebug Level = iota - 1
+	LevelDebug Level = -4
 	// LevelInfo is logger info level.
-	LevelInfo
+	LevelInfo Level = 0
 	// LevelWarn is logger warn level.
-	LevelWarn
+	LevelWarn Level = 4
 	// LevelError is logger error level.
-	LevelError
+	LevelError Level = 8
+	// LevelPanic is logger panic level.
+	LevelPanic Level = 10
 	// LevelFatal is logger fatal level
-	LevelFatal
+	LevelFatal Level = 12
 )
 
 func (l Level) Key() string {
@@ -35,10 +58,12 @@ func (l Level) String() string {
 		return "WARN"
 	case LevelError:
 		return "ERROR"
+	case LevelPanic:
+		return "PANIC"
 	case LevelFatal:
 		return "FATAL"
 	default:
-		return ""
+		return lookupLevel(l).name
 	}
 }
 
@@ -52,10 +77,12 @@ func (l Level) Abbr() string {
 		return "WRN"
 	case LevelError:
 		return "ERR"
+	case LevelPanic:
+		return "PNC"
 	case LevelFatal:
 		return "FTL"
 	default:
-		return ""
+		return lookupLevel(l).abbr
 	}
 }
 
@@ -69,26 +96,255 @@ func (l Level) Char() string {
 		return "W"
 	case LevelError:
 		return "E"
+	case LevelPanic:
+		return "P"
 	case LevelFatal:
 		return "F"
 	default:
-		return ""
+		return lookupLevel(l).char
 	}
 }
 
 // ParseLevel parses a level string into a logger Level value.
+// Unknown levels are parsed as LevelInfo, use ParseLevelStrict to detect them.
 func ParseLevel(s string) Level {
+	if level, ok := parseLevel(s); ok {
+		return level
+	}
+	return LevelInfo
+}
+
+// ParseLevelStrict parses a level name, case-insensitively, or a level number
+// into a logger Level value. Only the built-in levels and the levels registered
+// with RegisterLevel are accepted, by name or by number, such as "warn" or "4";
+// it returns an error for other levels.
+func ParseLevelStrict(s string) (Level, error) {
+	if level, ok := parseLevel(s); ok {
+		return level, nil
+	}
+	if n, err := strconv.ParseInt(s, 10, 8); err == nil && isKnownLevel(Level(n)) {
+		return Level(n), nil
+	}
+	return LevelInfo, fmt.Errorf("mo: unknown level %q", s)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+// Levels without a name, such as unregistered custom levels, are marshaled as numbers,
+// which UnmarshalText rejects.
+func (l Level) MarshalText() ([]byte, error) {
+	if s := l.String(); s != "" {
+		return []byte(strings.ToLower(s)), nil
+	}
+	return []byte(strconv.Itoa(int(l))), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using ParseLevelStrict.
+func (l *Level) UnmarshalText(text []byte) error {
+	level, err := ParseLevelStrict(string(text))
+	if err != nil {
+		return err
+	}
+	*l = level
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, encoding the level as a string.
+func (l Level) MarshalJSON() ([]byte, error) {
+	text, err := l.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts a level string or number,
+// see ParseLevelStrict.
+func (l *Level) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		var n int8
+		if json.Unmarshal(data, &n) != nil || !isKnownLevel(Level(n)) {
+			return fmt.Errorf("mo: invalid level %s", data)
+		}
+		*l = Level(n)
+		return nil
+	}
+	return l.UnmarshalText([]byte(s))
+}
+
+// Set implements flag.Value.
+func (l *Level) Set(s string) error {
+	return l.UnmarshalText([]byte(s))
+}
+
+// parseLevel parses a level string and reports whether it names a known level.
+func parseLevel(s string) (Level, bool) {
+	if level, ok := parseBuiltinLevel(s); ok {
+		return level, true
+	}
+	if level, ok := levelsByName()[strings.ToUpper(s)]; ok {
+		return level, true
+	}
+	return LevelInfo, false
+}
+
+// isKnownLevel reports whether l is a built-in or registered level.
+func isKnownLevel(l Level) bool {
+	return isBuiltinLevel(l) || lookupLevel(l).name != ""
+}
+
+func isBuiltinLevel(l Level) bool {
+	switch l {
+	case LevelDebug, LevelInfo, LevelWarn, LevelError, LevelPanic, LevelFatal:
+		return true
+	}
+	return false
+}
+
+func parseBuiltinLevel(s string) (Level, bool) {
 	switch strings.ToUpper(s) {
 	case "DEBUG":
-		return LevelDebug
+		return LevelDebug, true
 	case "INFO":
-		return LevelInfo
+		return LevelInfo, true
 	case "WARN":
-		return LevelWarn
+		return LevelWarn, true
 	case "ERROR":
-		return LevelError
+		return LevelError, true
+	case "PANIC":
+		return LevelPanic, true
 	case "FATAL":
-		return LevelFatal
+		return LevelFatal, true
 	}
-	return LevelInfo
+	return LevelInfo, false
+}
+
+// levelDesc describes a level registered with RegisterLevel.
+type levelDesc struct {
+	name string
+	abbr string
+	char string
+}
+
+// customLevels holds the registered levels. It is replaced as a whole on registration
+// so that lookups on the logging path don't need to lock.
+var (
+	customLevelsMu sync.Mutex
+	customLevels   atomic.Value // customLevelTable
+)
+
+type customLevelTable struct {
+	byLevel map[Level]levelDesc
+	byName  map[string]Level
+}
+
+func loadCustomLevels() customLevelTable {
+	t, _ := customLevels.Load().(customLevelTable)
+	return t
+}
+
+func lookupLevel(l Level) levelDesc {
+	return loadCustomLevels().byLevel[l]
+}
+
+func levelsByName() map[string]Level {
+	return loadCustomLevels().byName
+}
+
+// RegisterLevel registers a custom level, such as a Trace level below LevelDebug
+// or a Notice level between LevelInfo and LevelWarn. The name is used by String
+// and ParseLevel, abbr by Abbr and char by Char; empty abbr and char are derived
+// from the name. Registering a level again replaces its description.
+// Built-in levels and their names cannot be registered.
+func RegisterLevel(level Level, name, abbr, char string) error {
+	name = strings.ToUpper(name)
+	if name == "" {
+		return fmt.Errorf("mo: empty name for level %d", level)
+	}
+	if isBuiltinLevel(level) {
+		return fmt.Errorf("mo: level %s is a built-in level", level)
+	}
+	if abbr == "" {
+		abbr = name
+		if len(abbr) > 3 {
+			abbr = abbr[:3]
+		}
+	}
+	if char == "" {
+		char = name[:1]
+	}
+
+	customLevelsMu.Lock()
+	defer customLevelsMu.Unlock()
+
+	if other, ok := parseBuiltinLevel(name); ok {
+		return fmt.Errorf("mo: level name %q is used by level %s", name, other)
+	}
+	old := loadCustomLevels()
+	if other, ok := old.byName[name]; ok && other != level {
+		return fmt.Errorf("mo: level name %q is used by level %s", name, other)
+	}
+	t := customLevelTable{
+		byLevel: make(map[Level]levelDesc, len(old.byLevel)+1),
+		byName:  make(map[string]Level, len(old.byName)+1),
+	}
+	for l, desc := range old.byLevel {
+		if l != level {
+			t.byLevel[l] = desc
+			t.byName[desc.name] = l
+		}
+	}
+	t.byLevel[level] = levelDesc{name: name, abbr: strings.ToUpper(abbr), char: strings.ToUpper(char)}
+	t.byName[name] = level
+	customLevels.Store(t)
+	return nil
+}
+
+// AtomicLevel is a Level that can be read and changed safely from multiple goroutines.
+type AtomicLevel struct {
+	v int32
+}
+
+// NewAtomicLevel returns an AtomicLevel set to the given level.
+func NewAtomicLevel(level Level) *AtomicLevel {
+	return &AtomicLevel{v: int32(level)}
+}
+
+// Level returns the current level.
+func (a *AtomicLevel) Level() Level {
+	return Level(atomic.LoadInt32(&a.v))
+}
+
+// SetLevel changes the current level.
+func (a *AtomicLevel) SetLevel(level Level) {
+	atomic.StoreInt32(&a.v, int32(level))
+}
+
+// Enabled returns whether the specified level is at or above the current level.
+func (a *AtomicLevel) Enabled(level Level) bool {
+	return level >= a.Level()
+}
+
+func (a *AtomicLevel) String() string {
+	return a.Level().String()
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (a *AtomicLevel) MarshalText() ([]byte, error) {
+	return a.Level().MarshalText()
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (a *AtomicLevel) UnmarshalText(text []byte) error {
+	level, err := ParseLevelStrict(string(text))
+	if err != nil {
+		return err
+	}
+	a.SetLevel(level)
+	return nil
+}
+
+// Set implements flag.Value.
+func (a *AtomicLevel) Set(s string) error {
+	return a.UnmarshalText([]byte(s))
 }
@@ -0,0 +1,177 @@
+package mo
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestRegisterLevel(t *testing.T) {
+	const (
+		levelTrace  = LevelDebug - 4
+		levelNotice = LevelInfo + 2
+	)
+	if err := RegisterLevel(levelTrace, "trace", "", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterLevel(levelNotice, "Notice", "ntc", "n"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		level            Level
+		name, abbr, char string
+	}{
+		{levelTrace, "TRACE", "TRA", "T"},
+		{levelNotice, "NOTICE", "NTC", "N"},
+	}
+	for _, tt := range tests {
+		if tt.level.String() != tt.name || tt.level.Abbr() != tt.abbr || tt.level.Char() != tt.char {
+			t.Errorf("level %d = %s %s %s, want %s %s %s", tt.level,
+				tt.level.String(), tt.level.Abbr(), tt.level.Char(), tt.name, tt.abbr, tt.char)
+		}
+		if got := ParseLevel(strings.ToLower(tt.name)); got != tt.level {
+			t.Errorf("ParseLevel(%q) = %d, want %d", tt.name, got, tt.level)
+		}
+		if got, err := ParseLevelStrict(tt.name); err != nil || got != tt.level {
+			t.Errorf("ParseLevelStrict(%q) = %d, %v", tt.name, got, err)
+		}
+	}
+
+	// Registering a level again replaces its description and frees its old name.
+	if err := RegisterLevel(levelTrace, "finest", "", ""); err != nil {
+		t.Fatal(err)
+	}
+	if levelTrace.String() != "FINEST" || ParseLevel("trace") != LevelInfo {
+		t.Errorf("re-registered level = %s", levelTrace)
+	}
+
+	bad := []struct {
+		level Level
+		name  string
+	}{
+		{LevelInfo, "information"},  // built-in level
+		{LevelInfo + 1, "info"},     // built-in name
+		{LevelInfo + 1, "NOTICE"},   // name of another custom level
+		{LevelInfo + 1, ""},         // empty name
+		{LevelFatal, "catastrophe"}, // built-in level
+	}
+	for _, tt := range bad {
+		if err := RegisterLevel(tt.level, tt.name, "", ""); err == nil {
+			t.Errorf("RegisterLevel(%d, %q) should fail", tt.level, tt.name)
+		}
+	}
+	if (LevelInfo + 1).String() != "" {
+		t.Errorf("failed registration registered level %s", LevelInfo+1)
+	}
+}
+
+func TestParseLevelStrict(t *testing.T) {
+	tests := []struct {
+		in    string
+		level Level
+		ok    bool
+	}{
+		{"debug", LevelDebug, true},
+		{"INFO", LevelInfo, true},
+		{"Warn", LevelWarn, true},
+		{"error", LevelError, true},
+		{"panic", LevelPanic, true},
+		{"fatal", LevelFatal, true},
+		{"8", LevelError, true},
+		{"-4", LevelDebug, true},
+		{"42", 0, false},
+		{"100", 0, false},
+		{"1000", 0, false},
+		{"verbose", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		level, err := ParseLevelStrict(tt.in)
+		if (err == nil) != tt.ok || (tt.ok && level != tt.level) {
+			t.Errorf("ParseLevelStrict(%q) = %s, %v", tt.in, level, err)
+		}
+		if !tt.ok && ParseLevel(tt.in) != LevelInfo {
+			t.Errorf("ParseLevel(%q) should fall back to info", tt.in)
+		}
+	}
+}
+
+func TestLevel_Text(t *testing.T) {
+	for _, level := range []Level{LevelDebug, LevelInfo, LevelWarn, LevelError, LevelPanic, LevelFatal} {
+		text, err := level.MarshalText()
+		if err != nil || string(text) != strings.ToLower(level.String()) {
+			t.Errorf("MarshalText(%s) = %q, %v", level, text, err)
+		}
+		var got Level
+		if err := got.UnmarshalText(text); err != nil || got != level {
+			t.Errorf("UnmarshalText(%q) = %s, %v", text, got, err)
+		}
+	}
+
+	// Unregistered levels are marshaled as numbers and rejected when unmarshaled.
+	text, _ := Level(3).MarshalText()
+	if string(text) != "3" {
+		t.Errorf("MarshalText(3) = %q", text)
+	}
+	level := LevelWarn
+	if err := level.UnmarshalText(text); err == nil || level != LevelWarn {
+		t.Errorf("UnmarshalText(%q) = %s, %v", text, level, err)
+	}
+}
+
+func TestLevel_JSON(t *testing.T) {
+	type config struct {
+		Level Level `json:"level"`
+	}
+	b, err := json.Marshal(config{Level: LevelWarn})
+	if err != nil || string(b) != `{"level":"warn"}` {
+		t.Fatalf("Marshal = %s, %v", b, err)
+	}
+
+	tests := []struct {
+		in    string
+		level Level
+		ok    bool
+	}{
+		{`{"level":"warn"}`, LevelWarn, true},
+		{`{"level":"ERROR"}`, LevelError, true},
+		{`{"level":-4}`, LevelDebug, true},
+		{`{"level":"100"}`, 0, false},
+		{`{"level":100}`, 0, false},
+		{`{"level":1000}`, 0, false},
+		{`{"level":"verbose"}`, 0, false},
+		{`{"level":true}`, 0, false},
+	}
+	for _, tt := range tests {
+		var c config
+		err := json.Unmarshal([]byte(tt.in), &c)
+		if (err == nil) != tt.ok || (tt.ok && c.Level != tt.level) {
+			t.Errorf("Unmarshal(%s) = %s, %v", tt.in, c.Level, err)
+		}
+	}
+}
+
+func TestLevel_Flag(t *testing.T) {
+	level := LevelInfo
+	atomic := NewAtomicLevel(LevelInfo)
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Var(&level, "level", "log level")
+	fs.Var(atomic, "atomic-level", "log level")
+
+	if err := fs.Parse([]string{"-level", "debug", "-atomic-level", "error"}); err != nil {
+		t.Fatal(err)
+	}
+	if level != LevelDebug || atomic.Level() != LevelError {
+		t.Errorf("flags parsed as %s and %s", level, atomic)
+	}
+	if err := fs.Parse([]string{"-level", "loud"}); err == nil {
+		t.Error("unknown level accepted")
+	}
+	if err := fs.Parse([]string{"-atomic-level", "42"}); err == nil || atomic.Level() != LevelError {
+		t.Error("unknown level accepted")
+	}
+}
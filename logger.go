@@ -3,6 +3,11 @@ package mo
 import (
 	"context"
 	"fmt"
+	"math"
+	"os"
+	"runtime"
+	"sync"
+	"sync/atomic"
 )
 
 func sprint(a ...interface{}) string {
@@ -16,111 +21,425 @@ func sprintf(format string, a ...interface{}) string {
 
 // New creates a new Logger instance with the specified context, recorder, and base key-value pairs.
 func NewLogger(out Recorder, kv ...Field) *Logger {
-	return &Logger{
-		base:  kv,
-		level: LevelDebug,
-		out:   out,
-	}
+	l := &Logger{core: newLoggerCore()}
+	l.SetAtomicLevel(NewAtomicLevel(LevelDebug))
+	l.SetBase(kv...)
+	l.SetRecorder(out)
+	return l
 }
 
 // Logger is a logging client that provides methods for emitting log messages at different levels.
+//
+// The level, levels, base and recorder of a Logger may be changed while other goroutines are logging.
+// The zero Logger logs at LevelDebug, without a recorder until SetRecorder is called.
 type Logger struct {
-	base  []Field  // Base key-value pairs added to all log messages
-	level Level    // Minimum log level to emit
-	out   Recorder // Recorder for outputting log messages
+	name       string       // Dotted name of the logger, see Named
+	base       atomic.Value // Base key-value pairs added to all log messages, []Field
+	level      atomic.Value // Minimum log level to emit, *AtomicLevel
+	callerSkip int          // Number of additional stack frames to skip when capturing the caller
+	core       *loggerCore  // Settings shared with the child loggers, see shared
+	once       sync.Once    // Initializes core and level of a zero Logger
+}
+
+// shared returns the settings shared with the child loggers, initializing the
+// settings and the level of a zero Logger to their defaults.
+func (l *Logger) shared() *loggerCore {
+	l.once.Do(func() {
+		if l.core == nil {
+			l.core = newLoggerCore()
+		}
+		if l.level.Load() == nil {
+			l.level.Store(NewAtomicLevel(LevelDebug))
+		}
+	})
+	return l.core
+}
+
+// loggerCore holds the settings shared by a Logger and the loggers derived from it
+// with With and Named, so that changing them on any of these loggers changes them for all.
+type loggerCore struct {
+	levels atomic.Value // Per-name level overrides, levelsValue
+	raw    atomic.Value // Recorder set by SetRecorder, recorderValue
+	out    atomic.Value // Recorder for outputting log messages, raw wrapped by the middlewares, recorderValue
+	opts   atomic.Value // Options changed by the setters below, *options
+	mu     sync.Mutex   // Serializes updates of opts and the recorder
+}
+
+func newLoggerCore() *loggerCore {
+	c := &loggerCore{}
+	c.opts.Store(&options{exit: os.Exit, stackLevel: stackDisabled})
+	return c
+}
+
+// options holds the settings of a Logger shared with its child loggers.
+// An options value is never modified once stored, updates store a modified copy.
+type options struct {
+	exit       func(int)    // Function called by Fatal methods
+	caller     bool         // Add the caller of log calls as the CallerKey field
+	stackLevel Level        // Minimum level of log messages with a stack trace
+	mws        []Middleware // Middlewares wrapping the recorder, see Use
+}
+
+func (l *Logger) options() *options {
+	return l.shared().opts.Load().(*options)
+}
+
+// update stores a copy of the options modified by fn.
+func (l *Logger) update(fn func(o *options)) {
+	c := l.shared()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	o := *l.options()
+	fn(&o)
+	c.opts.Store(&o)
+}
+
+// recorderValue wraps a Recorder so that atomic.Value always stores the same concrete type.
+type recorderValue struct {
+	Recorder
+}
+
+// levelsValue wraps a *Levels so that atomic.Value can store nil.
+type levelsValue struct {
+	*Levels
+}
+
+// Enabled returns whether the specified log level is enabled, see EffectiveLevel.
+func (l *Logger) Enabled(level Level) bool {
+	return level >= l.EffectiveLevel()
+}
+
+// EffectiveLevel returns the minimum log level emitted by the logger: the level set
+// for a prefix of the logger's name in its Levels, or else the logger's own level.
+func (l *Logger) EffectiveLevel() Level {
+	if levels := l.Levels(); levels != nil {
+		if min, ok := levels.Lookup(l.name); ok {
+			return min
+		}
+	}
+	return l.Level()
 }
 
-// Enabled returns whether the specified log level is enabled.
-func (l Logger) Enabled(level Level) bool {
-	return level >= l.level
+// Name returns the dotted name of the logger, or "" for an unnamed logger.
+func (l *Logger) Name() string {
+	return l.name
+}
+
+// With returns a child Logger that adds the given key-value pairs to all log messages.
+//
+// The child starts at the parent's current level; changing the level or base of the child
+// does not affect the parent, use SetAtomicLevel to share a level. The recorder, middlewares,
+// levels and the settings such as SetCaller are shared: changing them on the parent or on
+// any child changes them for all of these loggers.
+func (l *Logger) With(kv ...Field) *Logger {
+	parent := l.Base()
+	base := make([]Field, 0, len(parent)+len(kv))
+	base = append(base, parent...)
+	base = append(base, kv...)
+	child := &Logger{name: l.name, callerSkip: l.callerSkip, core: l.shared()}
+	child.level.Store(NewAtomicLevel(l.Level()))
+	child.base.Store(base)
+	return child
+}
+
+// Named returns a child Logger whose name is the parent's name joined with name by a dot,
+// such as "app.db.pool". The name is added to all log messages as the LoggerKey field.
+func (l *Logger) Named(name string) *Logger {
+	child := l.With()
+	if name == "" {
+		return child
+	}
+	if l.name == "" {
+		child.name = name
+	} else {
+		child.name = l.name + "." + name
+	}
+	return child
+}
+
+// Levels returns the per-name level overrides of the logger, or nil if none are set.
+func (l *Logger) Levels() *Levels {
+	levels, _ := l.shared().levels.Load().(levelsValue)
+	return levels.Levels
+}
+
+// SetLevels sets the per-name level overrides consulted by the logger and the
+// loggers derived from it with With or Named, before and after the call.
+func (l *Logger) SetLevels(levels *Levels) {
+	l.shared().levels.Store(levelsValue{levels})
+}
+
+// Level returns the minimum log level to emit.
+func (l *Logger) Level() Level {
+	return l.AtomicLevel().Level()
 }
 
 // SetLevel sets the minimum log level to emit.
+// The change is visible to the loggers sharing the same AtomicLevel, see SetAtomicLevel.
 func (l *Logger) SetLevel(level Level) {
-	l.level = level
+	l.AtomicLevel().SetLevel(level)
 }
 
-// SetRecorder sets the recorder used to output log messages.
+// AtomicLevel returns the AtomicLevel holding the minimum log level to emit.
+func (l *Logger) AtomicLevel() *AtomicLevel {
+	l.shared()
+	return l.level.Load().(*AtomicLevel)
+}
+
+// SetAtomicLevel makes the logger use level as its minimum log level to emit.
+// Loggers sharing an AtomicLevel change their level together, for example:
+//
+//	child := logger.Named("db")
+//	child.SetAtomicLevel(logger.AtomicLevel())
+func (l *Logger) SetAtomicLevel(level *AtomicLevel) {
+	if level == nil {
+		level = NewAtomicLevel(LevelDebug)
+	}
+	l.shared()
+	l.level.Store(level)
+}
+
+// Recorder returns the recorder set by SetRecorder, without the middlewares added by Use.
+func (l *Logger) Recorder() Recorder {
+	out, _ := l.shared().raw.Load().(recorderValue)
+	return out.Recorder
+}
+
+// recorder returns the recorder wrapped by the middlewares.
+func (l *Logger) recorder() Recorder {
+	out, _ := l.shared().out.Load().(recorderValue)
+	return out.Recorder
+}
+
+// SetRecorder sets the recorder used to output log messages by the logger and the
+// loggers sharing its settings, see With. It is wrapped by the middlewares added by Use.
 func (l *Logger) SetRecorder(out Recorder) {
-	l.out = out
+	c := l.shared()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.raw.Store(recorderValue{out})
+	c.out.Store(recorderValue{chain(out, l.middlewares())})
+}
+
+// Use adds middlewares wrapping the recorder of the logger, such that log messages
+// pass through the middlewares in the order they were added before reaching the recorder.
+// The middlewares are shared with the loggers derived from the logger with With or Named.
+func (l *Logger) Use(mw ...Middleware) {
+	c := l.shared()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	o := *l.options()
+	o.mws = append(append(make([]Middleware, 0, len(o.mws)+len(mw)), o.mws...), mw...)
+	c.opts.Store(&o)
+	c.out.Store(recorderValue{chain(l.Recorder(), o.mws)})
+}
+
+// middlewares returns the middlewares added by Use.
+func (l *Logger) middlewares() []Middleware {
+	return l.options().mws
+}
+
+// SetExitFunc sets the function called with exit code 1 after a message is logged by the
+// Fatal methods of Helper and the package-level Fatal functions. It defaults to os.Exit.
+// Tests can use it to intercept fatal paths, and services to run shutdown logic or choose the exit code.
+func (l *Logger) SetExitFunc(fn func(code int)) {
+	if fn == nil {
+		fn = os.Exit
+	}
+	l.update(func(o *options) {
+		o.exit = fn
+	})
+}
+
+// SetCaller sets whether the caller of each log call is added to log messages as the
+// CallerKey field. The caller is captured once at the call site, and recorders can
+// read its file, line and function with Field.Frame.
+func (l *Logger) SetCaller(enabled bool) {
+	l.update(func(o *options) {
+		o.caller = enabled
+	})
+}
+
+// stackDisabled is the stack trace level of loggers without stack traces.
+const stackDisabled Level = math.MaxInt8
+
+// SetStacktrace sets the minimum level of log messages to which the stack trace of
+// the log call is added as the StacktraceKey field, such as LevelError.
+// Stack traces are disabled by default; a level above LevelFatal disables them again.
+func (l *Logger) SetStacktrace(level Level) {
+	l.update(func(o *options) {
+		o.stackLevel = level
+	})
+}
+
+// AddCallerSkip returns a child Logger that skips n additional stack frames when capturing
+// the caller, for use by functions that wrap the logging methods.
+func (l *Logger) AddCallerSkip(n int) *Logger {
+	child := l.With()
+	child.callerSkip += n
+	return child
+}
+
+// exit flushes the recorder so that the fatal message is not lost and calls the exit function.
+func (l *Logger) exit(code int) {
+	if err := l.Sync(); err != nil {
+		fmt.Fprintf(os.Stderr, "flush failed: %v\n", err)
+	}
+	l.options().exit(code)
+}
+
+// Sync flushes the log messages buffered by the recorder, see Flusher.
+func (l *Logger) Sync() error {
+	return FlushRecorder(l.recorder())
+}
+
+// Close flushes and closes the recorder, see Closer. It returns ctx.Err() if ctx is
+// done before the recorder is closed. The logger should not be used after Close.
+func (l *Logger) Close(ctx context.Context) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- CloseRecorder(l.recorder())
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Base returns the base key-value pairs added to all log messages.
+// The returned slice must not be modified.
+func (l *Logger) Base() []Field {
+	base, _ := l.base.Load().([]Field)
+	return base
 }
 
 // SetBase sets the base key-value pairs added to all log messages.
 func (l *Logger) SetBase(kv ...Field) {
-	l.base = kv
+	base := make([]Field, len(kv))
+	copy(base, kv)
+	l.base.Store(base)
 }
 
 // log is the internal method for logging messages at the specified level.
 // Deprecated: use Print, Printf or Printw instead.
-func (l Logger) Log(ctx context.Context, level Level, formatting bool, format string, args []interface{}, kv []Field) {
-	if !l.Enabled(level) || l.out == nil {
-		return
-	}
-
-	msg := ""
+func (l *Logger) Log(ctx context.Context, level Level, formatting bool, format string, args []interface{}, kv []Field) {
 	if formatting {
-		msg = sprintf(format, args...)
+		l.printf(ctx, level, format, args, kv)
 	} else {
-		msg = sprint(args...)
+		l.print(ctx, level, args, kv)
 	}
+}
 
-	kvs := make([]Field, 0, len(l.base)+len(kv))
-	kvs = append(kvs, l.base...)
-	kvs = append(kvs, kv...)
+// Print logs a message at the specified level.
+func (l *Logger) Print(ctx context.Context, level Level, a ...interface{}) {
+	l.print(ctx, level, a, nil)
+}
 
-	for i, v := range kvs {
-		if fn, ok := v.Value().(Valuer); ok {
-			kvs[i][1] = fn(ctx)
-		}
-	}
-	l.out.Log(ctx, level, msg, kvs)
+// Printf logs a formatted message at the specified level.
+func (l *Logger) Printf(ctx context.Context, level Level, format string, a ...interface{}) {
+	l.printf(ctx, level, format, a, nil)
 }
 
-// Print logs a message at the specified level.
-func (l Logger) Print(ctx context.Context, level Level, a ...interface{}) {
-	if !l.Enabled(level) || l.out == nil {
+// Printw logs a message with key-value pairs at the specified level.
+func (l *Logger) Printw(ctx context.Context, level Level, msg string, kv ...Field) {
+	l.printw(ctx, level, msg, kv)
+}
+
+// The print, printf and printw methods must be called directly by the exported logging
+// methods and functions, so that Caller valuers and the caller captured with SetCaller
+// see the same number of stack frames above the user's call site.
+
+func (l *Logger) print(ctx context.Context, level Level, a []interface{}, kv []Field) {
+	out := l.recorder()
+	if out == nil || !l.Enabled(level) {
 		return
 	}
 
-	kvs := append(make([]Field, 0, len(l.base)), l.base...)
+	kvs := l.fields(ctx, level, kv)
 	for i, v := range kvs {
-		if fn, ok := v.Value().(Valuer); ok {
-			kvs[i][1] = fn(ctx)
+		if fn, ok := v.valuer(); ok {
+			v = Any(v.key, fn(ctx))
 		}
+		kvs[i] = v.resolve()
 	}
 
-	l.out.Log(ctx, level, sprint(a...), kvs)
+	out.Log(ctx, level, sprint(a...), l.addCallsite(level, kvs))
 }
 
-// Printf logs a formatted message at the specified level.
-func (l Logger) Printf(ctx context.Context, level Level, format string, a ...interface{}) {
-	if !l.Enabled(level) || l.out == nil {
+func (l *Logger) printf(ctx context.Context, level Level, format string, a []interface{}, kv []Field) {
+	out := l.recorder()
+	if out == nil || !l.Enabled(level) {
 		return
 	}
 
-	kvs := append(make([]Field, 0, len(l.base)), l.base...)
+	kvs := l.fields(ctx, level, kv)
 	for i, v := range kvs {
-		if fn, ok := v.Value().(Valuer); ok {
-			kvs[i][1] = fn(ctx)
+		if fn, ok := v.valuer(); ok {
+			v = Any(v.key, fn(ctx))
 		}
+		kvs[i] = v.resolve()
 	}
 
-	l.out.Log(ctx, level, sprintf(format, a...), kvs)
+	out.Log(ctx, level, sprintf(format, a...), l.addCallsite(level, kvs))
 }
 
-// Printw logs a message with key-value pairs at the specified level.
-func (l Logger) Printw(ctx context.Context, level Level, msg string, kv ...Field) {
-	if !l.Enabled(level) || l.out == nil {
+func (l *Logger) printw(ctx context.Context, level Level, msg string, kv []Field) {
+	out := l.recorder()
+	if out == nil || !l.Enabled(level) {
 		return
 	}
 
-	kvs := append(make([]Field, 0, len(l.base)+len(kv)), l.base...)
-	kvs = append(kvs, kv...)
+	kvs := l.fields(ctx, level, kv)
 	for i, v := range kvs {
-		if fn, ok := v.Value().(Valuer); ok {
-			kvs[i][1] = fn(ctx)
+		if fn, ok := v.valuer(); ok {
+			v = Any(v.key, fn(ctx))
 		}
+		kvs[i] = v.resolve()
+	}
+
+	out.Log(ctx, level, msg, l.addCallsite(level, kvs))
+}
+
+// fields returns the name, base, context and given key-value pairs in a new slice
+// with room for the caller and the stack trace when they are added to messages at level.
+// Valuers and LogValuers are resolved by the callers so that Caller keeps its stack depth.
+func (l *Logger) fields(ctx context.Context, level Level, kv []Field) []Field {
+	base := l.Base()
+	ctxFields := FieldsFromContext(ctx)
+	n := len(base) + len(ctxFields) + len(kv)
+	if l.name != "" {
+		n++
+	}
+	if opts := l.options(); opts.caller || level >= opts.stackLevel {
+		n += 2
 	}
+	if n == 0 {
+		return nil
+	}
+	kvs := make([]Field, 0, n)
+	if l.name != "" {
+		kvs = append(kvs, String(LoggerKey, l.name))
+	}
+	kvs = append(kvs, base...)
+	kvs = append(kvs, ctxFields...)
+	return append(kvs, kv...)
+}
 
-	l.out.Log(ctx, level, msg, kvs)
+// addCallsite appends the caller and the stack trace of the exported logging method to kvs if enabled.
+func (l *Logger) addCallsite(level Level, kvs []Field) []Field {
+	opts := l.options()
+	if opts.caller {
+		// Skip runtime.Callers, addCallsite, print and the exported logging method.
+		var pcs [1]uintptr
+		if runtime.Callers(4+l.callerSkip, pcs[:]) > 0 {
+			kvs = append(kvs, Field{key: CallerKey, typ: CallerType, num: int64(pcs[0])})
+		}
+	}
+	if level >= opts.stackLevel {
+		// Skip addCallsite, print and the exported logging method.
+		kvs = append(kvs, Field{key: StacktraceKey, typ: StackType, iface: captureStack(3 + l.callerSkip)})
+	}
+	return kvs
 }
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"strings"
 	"sync"
@@ -299,3 +300,518 @@ func Benchmark_JSON(b *testing.B) {
 		b.Errorf("expected %d writes, got %d", b.N, discard.WriteCount())
 	}
 }
+
+func TestLogger_ConcurrentReconfigure(t *testing.T) {
+	discard := &discardWriter{}
+	recorder := &stdRecorder{
+		stdout: discard,
+		stderr: discard,
+		pool: &sync.Pool{
+			New: func() interface{} {
+				return new(bytes.Buffer)
+			},
+		},
+	}
+	logger := NewLogger(recorder)
+	log := New(context.Background(), logger)
+	child := log.WithFields(Value("component", "db"))
+	levels := []Level{LevelDebug, LevelInfo, LevelWarn, LevelError}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				log.Infow("test message", fields...)
+				child.Errorf("test message %s", "test")
+				log.Named("worker").Debug("test message")
+				_ = log.Logger.Enabled(LevelInfo)
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	for i := 0; ; i++ {
+		select {
+		case <-done:
+			if discard.WriteCount() == 0 {
+				t.Error("expected writes while reconfiguring")
+			}
+			return
+		default:
+		}
+		logger.SetLevel(levels[i%len(levels)])
+		logger.SetBase(Value("i", i), Value("ts", Timestamp(time.RFC3339)))
+		if i%2 == 0 {
+			logger.SetRecorder(recorder)
+		} else {
+			logger.SetRecorder(newJSONRecorder(discard))
+		}
+		if i%3 == 0 {
+			logger.SetLevels(nil)
+		} else {
+			overrides, _ := NewLevels("worker=error")
+			logger.SetLevels(overrides)
+		}
+	}
+}
+
+func TestLogger_With(t *testing.T) {
+	r := &fieldsRecorder{}
+	logger := NewLogger(r, String("app", "demo"))
+	logger.SetLevel(LevelInfo)
+	log := New(context.Background(), logger)
+
+	child := log.WithFields(String("req", "1"))
+	sibling := logger.With(String("req", "2"))
+	child.Logger.SetLevel(LevelError)
+	child.Logger.SetBase(String("replaced", "x"))
+
+	if got := len(logger.Base()); got != 1 || logger.Base()[0].Str() != "demo" {
+		t.Errorf("parent base modified: %v", logger.Base())
+	}
+	if logger.Level() != LevelInfo {
+		t.Errorf("parent level modified: %s", logger.Level())
+	}
+
+	sibling.Printw(context.Background(), LevelInfo, "msg")
+	if len(r.kv) != 2 || r.kv[0].Str() != "demo" || r.kv[1].Str() != "2" {
+		t.Errorf("unexpected sibling fields %v", r.kv)
+	}
+	log.Info("msg")
+	if len(r.kv) != 1 || r.kv[0].Key() != "app" {
+		t.Errorf("unexpected parent fields %v", r.kv)
+	}
+}
+
+func TestLogger_WithShared(t *testing.T) {
+	logger := NewLogger(DefaultRecorder)
+	child := logger.Named("db").With(String("k", "v"))
+	skipped := child.AddCallerSkip(1)
+
+	// Settings changed on the parent after deriving the children reach them.
+	r := &fieldsRecorder{}
+	logger.SetRecorder(r)
+	var codes []int
+	logger.SetExitFunc(func(code int) { codes = append(codes, code) })
+	logger.SetCaller(true)
+	logger.SetStacktrace(LevelError)
+	levels, _ := NewLevels("db=error")
+	logger.SetLevels(levels)
+
+	child.Printw(context.Background(), LevelError, "msg")
+	if r.msg != "msg" {
+		t.Fatal("child does not use the recorder set on the parent")
+	}
+	if got := keys(r.kv); !strings.Contains(got, "caller=") || !strings.Contains(got, "stacktrace=") {
+		t.Errorf("child fields %s, want the caller and stack trace", got)
+	}
+	if child.Enabled(LevelWarn) || !logger.Enabled(LevelWarn) {
+		t.Error("child does not use the levels set on the parent")
+	}
+	New(context.Background(), skipped).Fatal("bye")
+	if len(codes) != 1 {
+		t.Error("child does not use the exit func set on the parent")
+	}
+
+	// And the other way around; the caller skip stays per logger.
+	r2 := &fieldsRecorder{}
+	child.SetRecorder(r2)
+	child.SetCaller(false)
+	logger.Printw(context.Background(), LevelInfo, "parent")
+	if r2.msg != "parent" || strings.Contains(keys(r2.kv), "caller=") {
+		t.Error("parent does not use the settings changed on the child")
+	}
+	if child.callerSkip != 0 || skipped.callerSkip != 1 {
+		t.Error("AddCallerSkip changed the parent")
+	}
+}
+
+func TestLogger_WithLevel(t *testing.T) {
+	logger := NewLogger(nil)
+	logger.SetLevel(LevelWarn)
+	child := logger.With(Value("k", "v")).Named("child")
+	if child.Enabled(LevelInfo) {
+		t.Error("child should start at the parent level")
+	}
+
+	child.SetLevel(LevelError)
+	if logger.Level() != LevelWarn {
+		t.Errorf("child changed the parent level to %s", logger.Level())
+	}
+	logger.SetLevel(LevelDebug)
+	if child.Level() != LevelError {
+		t.Errorf("parent changed the child level to %s", child.Level())
+	}
+
+	shared := logger.Named("shared")
+	shared.SetAtomicLevel(logger.AtomicLevel())
+	shared.SetLevel(LevelInfo)
+	if logger.Level() != LevelInfo {
+		t.Error("loggers sharing an AtomicLevel should change together")
+	}
+}
+
+// callerRecorder records the caller field of the last log message.
+type callerRecorder struct {
+	caller Field
+	valuer string
+}
+
+func (r *callerRecorder) Log(ctx context.Context, level Level, msg string, kv []Field) {
+	for _, v := range kv {
+		if v.Key() == CallerKey {
+			r.caller = v
+		}
+		if v.Key() == "valuer" {
+			r.valuer, _ = v.Value().(string)
+		}
+	}
+}
+
+func wrappedInfo(log *Helper, msg string) {
+	log.AddCallerSkip(1).Info(msg)
+}
+
+func TestLogger_Caller(t *testing.T) {
+	r := &callerRecorder{}
+	logger := NewLogger(r, Value("valuer", DefaultCaller))
+	logger.SetCaller(true)
+	log := New(context.Background(), logger)
+	prev := Default()
+	SetDefault(log)
+	defer SetDefault(prev)
+
+	calls := []func(){
+		func() { logger.Print(context.Background(), LevelInfo, "msg") },
+		func() { logger.Printf(context.Background(), LevelInfo, "msg %d", 1) },
+		func() { logger.Printw(context.Background(), LevelInfo, "msg") },
+		func() { log.Info("msg") },
+		func() { log.Warnfx(context.Background(), "msg %d", 1) },
+		func() { log.Logw(LevelError, "msg") },
+		func() { Infow("msg") },
+		func() { Errorx(context.Background(), "msg") },
+		func() { wrappedInfo(log, "msg") },
+	}
+	for i, call := range calls {
+		r.caller = Field{}
+		call()
+		if r.caller.Type() != CallerType {
+			t.Fatalf("call %d: missing caller field", i)
+		}
+		frame := r.caller.Frame()
+		if !strings.HasSuffix(frame.File, "logger_test.go") || !strings.Contains(frame.Function, "TestLogger_Caller") {
+			t.Errorf("call %d: unexpected caller %s %s:%d", i, frame.Function, frame.File, frame.Line)
+		}
+		// The Caller valuer can't skip the wrapper of the last call.
+		if i < len(calls)-1 && r.valuer != r.caller.Value() {
+			t.Errorf("call %d: Caller valuer %q and built-in caller %q differ", i, r.valuer, r.caller.Value())
+		}
+	}
+}
+
+// fieldsRecorder records the level, message and fields of the last log message.
+type fieldsRecorder struct {
+	level Level
+	msg   string
+	kv    []Field
+}
+
+func (r *fieldsRecorder) Log(ctx context.Context, level Level, msg string, kv []Field) {
+	r.level, r.msg, r.kv = level, msg, kv
+}
+
+type secret string
+
+func (s secret) LogValue() interface{} { return "***" }
+
+type user struct {
+	ID    int
+	Token secret
+}
+
+func (u *user) LogValue() interface{} { return u.Token }
+
+func TestLogger_LogValuer(t *testing.T) {
+	r := &fieldsRecorder{}
+	logger := NewLogger(r, Any("base", secret("base")))
+	var nilUser *user
+	logger.Printw(context.Background(), LevelInfo, "msg",
+		Any("user", &user{ID: 1, Token: "t0k3n"}),
+		Any("nil", nilUser),
+		Any("valuer", Valuer(func(context.Context) interface{} { return secret("v") })),
+	)
+
+	want := map[string]string{"base": "***", "user": "***", "valuer": "***"}
+	for _, v := range r.kv {
+		if v.Key() == "nil" {
+			if v.Type() != ErrorType {
+				t.Errorf("panicking LogValue gave %v field %v", v.Type(), v.Value())
+			}
+			continue
+		}
+		if v.Type() != StringType || v.Str() != want[v.Key()] {
+			t.Errorf("%s = %v (%v), want %q", v.Key(), v.Value(), v.Type(), want[v.Key()])
+		}
+	}
+}
+
+func TestLogger_Use(t *testing.T) {
+	r := &fieldsRecorder{}
+	var order []string
+	trace := func(name string) Middleware {
+		return func(next Recorder) Recorder {
+			return HookMiddleware(func(ctx context.Context, e *Entry) bool {
+				order = append(order, name)
+				e.Fields = append(e.Fields, String(name, "x"))
+				return e.Message != "drop"
+			})(next)
+		}
+	}
+
+	logger := NewLogger(r)
+	logger.Use(trace("a"), trace("b"))
+	child := logger.With()
+	logger.Use(trace("c"))
+	logger.Printw(context.Background(), LevelInfo, "msg")
+	if got := strings.Join(order, ","); got != "a,b,c" {
+		t.Fatalf("middlewares called in order %s", got)
+	}
+	if len(r.kv) != 3 || r.kv[2].Key() != "c" {
+		t.Fatalf("unexpected fields %v", r.kv)
+	}
+
+	// Replacing the recorder keeps the middlewares, which the child shares.
+	r2 := &fieldsRecorder{}
+	child.SetRecorder(r2)
+	order = nil
+	child.Printw(context.Background(), LevelInfo, "msg")
+	if got := strings.Join(order, ","); got != "a,b,c" || len(r2.kv) != 3 {
+		t.Fatalf("child middlewares %s, fields %v", got, r2.kv)
+	}
+	if child.Recorder() != Recorder(r2) || logger.Recorder() != Recorder(r2) {
+		t.Error("Recorder does not return the recorder set by SetRecorder")
+	}
+	logger.SetRecorder(r)
+
+	r.kv = nil
+	logger.Printw(context.Background(), LevelInfo, "drop")
+	if r.kv != nil {
+		t.Error("message dropped by a hook was recorded")
+	}
+}
+
+func TestHelper_Panic(t *testing.T) {
+	r := &fieldsRecorder{}
+	log := New(context.Background(), NewLogger(r))
+	prev := Default()
+	SetDefault(log)
+	defer SetDefault(prev)
+	ctx := context.Background()
+
+	calls := []struct {
+		name string
+		fn   func()
+		msg  string
+	}{
+		{"Panic", func() { log.Panic("boom", 1) }, "boom 1"},
+		{"Panicf", func() { log.Panicf("boom %d", 2) }, "boom 2"},
+		{"Panicw", func() { log.Panicw("boom", String("k", "v")) }, "boom"},
+		{"Panicx", func() { log.Panicx(ctx, "boom") }, "boom"},
+		{"Panicfx", func() { log.Panicfx(ctx, "boom %s", "x") }, "boom x"},
+		{"Panicwx", func() { log.Panicwx(ctx, "boom", String("k", "v")) }, "boom"},
+		{"mo.Panic", func() { Panic("boom", 1) }, "boom 1"},
+		{"mo.Panicf", func() { Panicf("boom %d", 2) }, "boom 2"},
+		{"mo.Panicw", func() { Panicw("boom", String("k", "v")) }, "boom"},
+		{"mo.Panicx", func() { Panicx(ctx, "boom") }, "boom"},
+		{"mo.Panicfx", func() { Panicfx(ctx, "boom %s", "x") }, "boom x"},
+		{"mo.Panicwx", func() { Panicwx(ctx, "boom", String("k", "v")) }, "boom"},
+	}
+	for _, c := range calls {
+		*r = fieldsRecorder{}
+		recovered := func() (p interface{}) {
+			defer func() { p = recover() }()
+			c.fn()
+			return nil
+		}()
+		if recovered != c.msg {
+			t.Errorf("%s panicked with %v, want %q", c.name, recovered, c.msg)
+		}
+		if r.level != LevelPanic || r.msg != c.msg {
+			t.Errorf("%s logged %q at %s, want %q at PANIC", c.name, r.msg, r.level, c.msg)
+		}
+	}
+}
+
+func TestHelper_Fatal(t *testing.T) {
+	r := &fieldsRecorder{}
+	logger := NewLogger(r)
+	var codes []int
+	logger.SetExitFunc(func(code int) { codes = append(codes, code) })
+	log := New(context.Background(), logger)
+	prev := Default()
+	SetDefault(log)
+	defer SetDefault(prev)
+	ctx := context.Background()
+
+	calls := []struct {
+		name string
+		fn   func()
+		msg  string
+	}{
+		{"Fatal", func() { log.Fatal("bye", 1) }, "bye 1"},
+		{"Fatalf", func() { log.Fatalf("bye %d", 2) }, "bye 2"},
+		{"Fatalw", func() { log.Fatalw("bye", String("k", "v")) }, "bye"},
+		{"Fatalx", func() { log.Fatalx(ctx, "bye") }, "bye"},
+		{"Fatalfx", func() { log.Fatalfx(ctx, "bye %s", "x") }, "bye x"},
+		{"Fatalwx", func() { log.Fatalwx(ctx, "bye", String("k", "v")) }, "bye"},
+		{"mo.Fatal", func() { Fatal("bye", 1) }, "bye 1"},
+		{"mo.Fatalf", func() { Fatalf("bye %d", 2) }, "bye 2"},
+		{"mo.Fatalw", func() { Fatalw("bye", String("k", "v")) }, "bye"},
+		{"mo.Fatalx", func() { Fatalx(ctx, "bye") }, "bye"},
+		{"mo.Fatalfx", func() { Fatalfx(ctx, "bye %s", "x") }, "bye x"},
+		{"mo.Fatalwx", func() { Fatalwx(ctx, "bye", String("k", "v")) }, "bye"},
+	}
+	for _, c := range calls {
+		*r = fieldsRecorder{}
+		codes = nil
+		c.fn()
+		if len(codes) != 1 || codes[0] != 1 {
+			t.Errorf("%s called the exit func with %v, want [1]", c.name, codes)
+		}
+		if r.level != LevelFatal || r.msg != c.msg {
+			t.Errorf("%s logged %q at %s, want %q at FATAL", c.name, r.msg, r.level, c.msg)
+		}
+	}
+
+	// Fatal messages are logged and the exit func called even below the logger level.
+	logger.SetLevel(LevelFatal + 1)
+	codes = nil
+	log.Fatal("bye")
+	if len(codes) != 1 {
+		t.Error("exit func not called for a disabled fatal message")
+	}
+}
+
+// syncRecorder records the calls to Log, Flush and Close in order.
+// Close blocks until block is closed, when block is set.
+type syncRecorder struct {
+	mu    sync.Mutex
+	calls []string
+	err   error
+	block chan struct{}
+}
+
+func (r *syncRecorder) record(call string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, call)
+}
+
+func (r *syncRecorder) Calls() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return strings.Join(r.calls, ",")
+}
+
+func (r *syncRecorder) Log(ctx context.Context, level Level, msg string, kv []Field) {
+	r.record("log")
+}
+
+func (r *syncRecorder) Flush() error {
+	r.record("flush")
+	return r.err
+}
+
+func (r *syncRecorder) Close() error {
+	if r.block != nil {
+		<-r.block
+	}
+	r.record("close")
+	return r.err
+}
+
+func TestCombine_FlushClose(t *testing.T) {
+	errFlush := errors.New("flush failed")
+	a, b, c := &syncRecorder{}, &syncRecorder{err: errFlush}, &fieldsRecorder{}
+	r := Combine(a, b, c)
+	r.Log(context.Background(), LevelInfo, "msg", nil)
+	if a.Calls() != "log" || b.Calls() != "log" || c.msg != "msg" {
+		t.Fatal("message not recorded by all recorders")
+	}
+
+	if err := FlushRecorder(r); err != errFlush {
+		t.Errorf("Flush() = %v, want %v", err, errFlush)
+	}
+	if err := CloseRecorder(r); err != errFlush {
+		t.Errorf("Close() = %v, want %v", err, errFlush)
+	}
+	if a.Calls() != "log,flush,close" || b.Calls() != "log,flush,close" {
+		t.Errorf("calls = %q and %q", a.Calls(), b.Calls())
+	}
+}
+
+func TestLogger_FatalFlushes(t *testing.T) {
+	r := &syncRecorder{}
+	logger := NewLogger(r)
+	logger.SetExitFunc(func(code int) { r.record("exit") })
+	New(context.Background(), logger).Fatal("bye")
+	if got := r.Calls(); got != "log,flush,exit" {
+		t.Errorf("calls = %q, want the recorder flushed before exit", got)
+	}
+}
+
+func TestLogger_Close(t *testing.T) {
+	r := &syncRecorder{}
+	if err := NewLogger(r).Close(context.Background()); err != nil || r.Calls() != "close" {
+		t.Errorf("Close() = %v, calls %q", err, r.Calls())
+	}
+
+	r = &syncRecorder{block: make(chan struct{})}
+	defer close(r.block)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := NewLogger(r).Close(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Close() = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestLogger_Zero(t *testing.T) {
+	var logger Logger
+	if !logger.Enabled(LevelDebug) || logger.Level() != LevelDebug || logger.EffectiveLevel() != LevelDebug {
+		t.Error("zero Logger should log at LevelDebug")
+	}
+	logger.Printw(context.Background(), LevelInfo, "dropped") // No recorder
+	logger.SetLevel(LevelInfo)
+	if logger.Enabled(LevelDebug) {
+		t.Error("SetLevel has no effect")
+	}
+
+	r := &fieldsRecorder{}
+	logger.SetRecorder(r)
+	child := logger.Named("child")
+	child.Printw(context.Background(), LevelInfo, "msg")
+	if r.msg != "msg" || child.Level() != LevelInfo {
+		t.Errorf("child logged %q at level %s", r.msg, child.Level())
+	}
+
+	var stacks Logger
+	stacks.SetRecorder(r)
+	stacks.Printw(context.Background(), LevelFatal, "msg")
+	if _, ok := stackOf(r.kv); ok {
+		t.Error("zero Logger should not add stack traces")
+	}
+
+	var codes []int
+	var fatal Logger
+	fatal.SetExitFunc(func(code int) { codes = append(codes, code) })
+	New(context.Background(), &fatal).Fatal("bye")
+	if len(codes) != 1 || codes[0] != 1 {
+		t.Errorf("exit func called with %v", codes)
+	}
+}
@@ -0,0 +1,56 @@
+package mo
+
+import "context"
+
+// Middleware wraps a Recorder to add behaviour such as enrichment, redaction, sampling
+// or metrics, see Logger.Use. The returned Recorder should implement Flusher and Closer
+// by flushing and closing next with FlushRecorder and CloseRecorder.
+type Middleware func(next Recorder) Recorder
+
+// Hook inspects or modifies a log message before it is recorded, see HookMiddleware.
+// It returns false to drop the message. The fields of the message belong to the log
+// call and may be modified in place.
+type Hook func(ctx context.Context, e *Entry) bool
+
+// HookMiddleware returns a Middleware that calls hook for each log message
+// and passes the messages it keeps to the next recorder.
+func HookMiddleware(hook Hook) Middleware {
+	return func(next Recorder) Recorder {
+		return &hookRecorder{hook: hook, next: next}
+	}
+}
+
+type hookRecorder struct {
+	hook Hook
+	next Recorder
+}
+
+func (r *hookRecorder) Log(ctx context.Context, level Level, msg string, kv []Field) {
+	e := Entry{Level: level, Message: msg, Fields: kv}
+	if r.hook(ctx, &e) {
+		r.next.Log(ctx, e.Level, e.Message, e.Fields)
+	}
+}
+
+// Flush flushes the next recorder if it implements Flusher.
+func (r *hookRecorder) Flush() error {
+	return FlushRecorder(r.next)
+}
+
+// Close closes the next recorder, see CloseRecorder.
+func (r *hookRecorder) Close() error {
+	return CloseRecorder(r.next)
+}
+
+// chain returns out wrapped by the middlewares, the first middleware being the outermost.
+func chain(out Recorder, mws []Middleware) Recorder {
+	if out == nil {
+		return nil
+	}
+	for i := len(mws) - 1; i >= 0; i-- {
+		if next := mws[i](out); next != nil {
+			out = next
+		}
+	}
+	return out
+}
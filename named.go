@@ -0,0 +1,111 @@
+package mo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// LoggerKey is the key of the field carrying the name of a named Logger.
+const LoggerKey = "logger"
+
+// Levels holds minimum log levels for named loggers, keyed by name prefix.
+//
+// A prefix matches a logger name when it is equal to the name or is followed
+// by a dot in it, so "app.db" matches "app.db" and "app.db.pool" but not
+// "app.dbx". The longest matching prefix wins. The "*" prefix sets the level of
+// the loggers whose name matches no other prefix, including unnamed loggers,
+// overriding their own level. Without it, they use their own level, see Logger.SetLevel.
+type Levels struct {
+	mu     sync.RWMutex
+	levels map[string]Level
+}
+
+// NewLevels returns a Levels parsed from spec, see Levels.Parse.
+func NewLevels(spec string) (*Levels, error) {
+	r := &Levels{}
+	if err := r.Parse(spec); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Set sets the minimum log level for loggers whose name matches prefix.
+func (r *Levels) Set(prefix string, level Level) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.levels == nil {
+		r.levels = make(map[string]Level)
+	}
+	r.levels[prefix] = level
+}
+
+// Delete removes the level set for prefix.
+func (r *Levels) Delete(prefix string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.levels, prefix)
+}
+
+// Parse sets levels from a comma separated list of prefix=level pairs,
+// such as "app.db=debug,*=info". Existing levels of other prefixes are kept.
+func (r *Levels) Parse(spec string) error {
+	parsed := make(map[string]Level)
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		idx := strings.IndexByte(item, '=')
+		if idx <= 0 {
+			return fmt.Errorf("mo: invalid level spec %q", item)
+		}
+		level, err := ParseLevelStrict(strings.TrimSpace(item[idx+1:]))
+		if err != nil {
+			return err
+		}
+		prefix := strings.TrimSpace(item[:idx])
+		if prefix != "*" && strings.Contains(prefix, "*") {
+			return fmt.Errorf("mo: invalid logger name prefix %q, only \"*\" may contain a wildcard", prefix)
+		}
+		parsed[prefix] = level
+	}
+	for prefix, level := range parsed {
+		r.Set(prefix, level)
+	}
+	return nil
+}
+
+// Lookup returns the level of the longest prefix matching name, or the level of the
+// "*" prefix if no other prefix matches.
+func (r *Levels) Lookup(name string) (Level, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for name != "" {
+		if level, ok := r.levels[name]; ok {
+			return level, true
+		}
+		idx := strings.LastIndexByte(name, '.')
+		if idx == -1 {
+			break
+		}
+		name = name[:idx]
+	}
+	if level, ok := r.levels["*"]; ok {
+		return level, true
+	}
+	return LevelInfo, false
+}
+
+// String returns the levels in the format accepted by Parse.
+func (r *Levels) String() string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	items := make([]string, 0, len(r.levels))
+	for prefix, level := range r.levels {
+		items = append(items, prefix+"="+strings.ToLower(level.String()))
+	}
+	sort.Strings(items)
+	return strings.Join(items, ",")
+}
@@ -0,0 +1,143 @@
+package mo
+
+import (
+	"context"
+	"testing"
+)
+
+func TestLogger_Named(t *testing.T) {
+	r := &fieldsRecorder{}
+	logger := NewLogger(r)
+	db := logger.Named("app").Named("db")
+	if db.Name() != "app.db" {
+		t.Fatalf("name = %q, want app.db", db.Name())
+	}
+	if logger.Named("").Name() != "" {
+		t.Error("empty name should keep the parent name")
+	}
+
+	db.Printw(context.Background(), LevelInfo, "msg")
+	if len(r.kv) != 1 || r.kv[0].Key() != LoggerKey || r.kv[0].Str() != "app.db" {
+		t.Errorf("unexpected fields %v", r.kv)
+	}
+}
+
+func TestLevels_Parse(t *testing.T) {
+	levels, err := NewLevels(" app.db = debug, app.http=WARN,,app=error ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := levels.String(); got != "app.db=debug,app.http=warn,app=error" {
+		t.Errorf("String() = %q", got)
+	}
+
+	// Parse keeps the other prefixes and fails without changes on errors.
+	if err := levels.Parse("app.db=info"); err != nil {
+		t.Fatal(err)
+	}
+	for _, spec := range []string{"app.db", "=debug", "app.db=verbose", "app.*=info", "app.db=debug,app=nope"} {
+		if err := levels.Parse(spec); err == nil {
+			t.Errorf("Parse(%q) should fail", spec)
+		}
+	}
+	if got := levels.String(); got != "app.db=info,app.http=warn,app=error" {
+		t.Errorf("String() = %q after Parse", got)
+	}
+
+	levels.Delete("app.http")
+	if got := levels.String(); got != "app.db=info,app=error" {
+		t.Errorf("String() = %q after Delete", got)
+	}
+
+	levels, err = NewLevels("app.db=debug,*=info")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := levels.String(); got != "*=info,app.db=debug" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestLevels_Lookup(t *testing.T) {
+	levels, _ := NewLevels("app=warn,app.db=debug")
+	tests := []struct {
+		name  string
+		level Level
+		ok    bool
+	}{
+		{"app", LevelWarn, true},
+		{"app.db", LevelDebug, true},
+		{"app.db.pool", LevelDebug, true},
+		{"app.dbx", LevelWarn, true},
+		{"app.http", LevelWarn, true},
+		{"apps", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		level, ok := levels.Lookup(tt.name)
+		if ok != tt.ok || (ok && level != tt.level) {
+			t.Errorf("Lookup(%q) = %s, %v, want %s, %v", tt.name, level, ok, tt.level, tt.ok)
+		}
+	}
+
+	// The "*" prefix applies to the names matching no other prefix.
+	levels.Set("*", LevelError)
+	for name, want := range map[string]Level{"app.db": LevelDebug, "apps": LevelError, "": LevelError} {
+		if level, ok := levels.Lookup(name); !ok || level != want {
+			t.Errorf("Lookup(%q) = %s, %v, want %s", name, level, ok, want)
+		}
+	}
+}
+
+func TestLogger_EffectiveLevel(t *testing.T) {
+	logger := NewLogger(nil)
+	levels, _ := NewLevels("app.db=debug")
+	logger.SetLevels(levels)
+	logger.SetLevel(LevelWarn)
+	db := logger.Named("app").Named("db")
+	http := logger.Named("app").Named("http")
+
+	if !db.Enabled(LevelDebug) || db.EffectiveLevel() != LevelDebug {
+		t.Error("prefix level should override the logger level")
+	}
+	if http.Enabled(LevelInfo) || logger.Enabled(LevelInfo) {
+		t.Error("loggers without a matching prefix should use their own level")
+	}
+
+	// SetLevel keeps working on loggers without a matching prefix.
+	logger.SetLevel(LevelDebug)
+	http.SetLevel(LevelDebug)
+	if !logger.Enabled(LevelDebug) || !http.Enabled(LevelDebug) {
+		t.Error("SetLevel has no effect")
+	}
+}
+
+func TestSetLevels_AfterNamed(t *testing.T) {
+	prev := Default()
+	SetDefault(New(context.Background(), NewLogger(nil)))
+	defer SetDefault(prev)
+
+	// Loggers derived before the levels are set, such as package-level loggers, use them.
+	db := Named("db")
+	http := Named("http")
+	levels, _ := NewLevels("db=debug,*=warn")
+	SetLevels(levels)
+	Default().Logger.SetLevel(LevelError)
+
+	if !db.Logger.Enabled(LevelDebug) {
+		t.Error("prefix level not applied to a logger derived before SetLevels")
+	}
+	if http.Logger.Enabled(LevelInfo) || !http.Logger.Enabled(LevelWarn) || !Enabled(LevelWarn) {
+		t.Error("the * level should apply to the loggers matching no other prefix")
+	}
+
+	// Changes to the registry apply immediately.
+	levels.Set("http", LevelDebug)
+	if !http.Logger.Enabled(LevelDebug) {
+		t.Error("registry change not applied")
+	}
+	SetLevels(nil)
+	if Enabled(LevelWarn) || !http.Logger.Enabled(LevelDebug) {
+		t.Error("loggers should use their own level without levels")
+	}
+}
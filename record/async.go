@@ -0,0 +1,176 @@
+package record
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/mengdu/mo"
+)
+
+// DropPolicy is the behaviour of Async when its queue is full.
+type DropPolicy uint8
+
+const (
+	// Block waits until there is room in the queue.
+	Block DropPolicy = iota
+	// DropNewest drops the entry being logged.
+	DropNewest
+	// DropOldest drops the oldest queued entry to make room for the entry being logged.
+	DropOldest
+	// DropBelowLevel drops the entry being logged if its level is below AsyncOptions.Level,
+	// and waits until there is room in the queue otherwise.
+	DropBelowLevel
+)
+
+// AsyncOptions configures an Async recorder.
+type AsyncOptions struct {
+	Size   int        // Maximum number of queued entries, defaults to 1024
+	Policy DropPolicy // Behaviour when the queue is full
+	Level  mo.Level   // Minimum level of entries never dropped by DropBelowLevel
+}
+
+// Async is a recorder that queues log messages and writes them to another recorder from a
+// background goroutine, so that a slow sink does not stall the goroutines logging messages.
+// Async must be created with NewAsync and closed with Close to stop the goroutine.
+type Async struct {
+	dropped uint64 // Accessed atomically, first for 64-bit alignment
+
+	inner mo.Recorder
+	opts  AsyncOptions
+
+	mu     sync.Mutex
+	cond   *sync.Cond // Signaled on every change of the queue, busy and closed
+	queue  []asyncEntry
+	head   int
+	n      int
+	seq    uint64 // Sequence number of the last queued entry
+	busy   uint64 // Sequence number of the entry being written, 0 when idle
+	closed bool
+	done   chan struct{} // Closed when the goroutine has written all entries
+}
+
+type asyncEntry struct {
+	seq   uint64
+	ctx   context.Context
+	level mo.Level
+	msg   string
+	kv    []mo.Field
+}
+
+// NewAsync returns an Async recorder writing to inner and starts its goroutine.
+func NewAsync(inner mo.Recorder, opts AsyncOptions) *Async {
+	if opts.Size <= 0 {
+		opts.Size = 1024
+	}
+	a := &Async{
+		inner: inner,
+		opts:  opts,
+		queue: make([]asyncEntry, opts.Size),
+		done:  make(chan struct{}),
+	}
+	a.cond = sync.NewCond(&a.mu)
+	go a.run()
+	return a
+}
+
+// Log implements the Recorder interface. The entry is dropped if the recorder is closed.
+func (a *Async) Log(ctx context.Context, level mo.Level, msg string, kv []mo.Field) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for !a.closed && a.n == len(a.queue) {
+		switch a.opts.Policy {
+		case DropNewest:
+			atomic.AddUint64(&a.dropped, 1)
+			return
+		case DropOldest:
+			a.pop()
+			atomic.AddUint64(&a.dropped, 1)
+		case DropBelowLevel:
+			if level < a.opts.Level {
+				atomic.AddUint64(&a.dropped, 1)
+				return
+			}
+			a.cond.Wait()
+		default:
+			a.cond.Wait()
+		}
+	}
+	if a.closed {
+		atomic.AddUint64(&a.dropped, 1)
+		return
+	}
+
+	a.seq++
+	a.queue[(a.head+a.n)%len(a.queue)] = asyncEntry{seq: a.seq, ctx: ctx, level: level, msg: msg, kv: kv}
+	a.n++
+	a.cond.Broadcast()
+}
+
+// pop removes and returns the oldest queued entry. It must be called with mu held.
+func (a *Async) pop() asyncEntry {
+	e := a.queue[a.head]
+	a.queue[a.head] = asyncEntry{}
+	a.head = (a.head + 1) % len(a.queue)
+	a.n--
+	return e
+}
+
+// run writes the queued entries to the inner recorder until the recorder is closed and the queue is empty.
+func (a *Async) run() {
+	defer close(a.done)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for {
+		for a.n == 0 && !a.closed {
+			a.cond.Wait()
+		}
+		if a.n == 0 {
+			return
+		}
+
+		e := a.pop()
+		a.busy = e.seq
+		a.cond.Broadcast()
+		a.mu.Unlock()
+		a.inner.Log(e.ctx, e.level, e.msg, e.kv)
+		a.mu.Lock()
+		a.busy = 0
+		a.cond.Broadcast()
+	}
+}
+
+// Dropped returns the number of entries dropped because the queue was full or the recorder was closed.
+func (a *Async) Dropped() uint64 {
+	return atomic.LoadUint64(&a.dropped)
+}
+
+// Flush waits until the entries queued before the call are written and flushes the inner recorder.
+func (a *Async) Flush() error {
+	a.mu.Lock()
+	seq := a.seq
+	// Entries are queued and written in sequence order.
+	for (a.n > 0 && a.queue[a.head].seq <= seq) || (a.busy != 0 && a.busy <= seq) {
+		a.cond.Wait()
+	}
+	a.mu.Unlock()
+	return mo.FlushRecorder(a.inner)
+}
+
+// Close writes the queued entries, stops the goroutine and closes the inner recorder.
+// Entries logged after Close are dropped.
+func (a *Async) Close() error {
+	a.mu.Lock()
+	closed := a.closed
+	a.closed = true
+	a.cond.Broadcast()
+	a.mu.Unlock()
+
+	<-a.done
+	if closed {
+		return nil
+	}
+	return mo.CloseRecorder(a.inner)
+}
@@ -0,0 +1,99 @@
+package record
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/mengdu/mo"
+)
+
+// gateRecorder records the messages, blocking until the gate is opened.
+type gateRecorder struct {
+	gate chan struct{}
+	mu   sync.Mutex
+	msgs []string
+}
+
+func (r *gateRecorder) Log(ctx context.Context, level mo.Level, msg string, kv []mo.Field) {
+	<-r.gate
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.msgs = append(r.msgs, msg)
+}
+
+func (r *gateRecorder) messages() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.msgs...)
+}
+
+func TestAsync(t *testing.T) {
+	tests := []struct {
+		policy  DropPolicy
+		want    []string
+		dropped uint64
+	}{
+		{DropNewest, []string{"0", "1", "2"}, 4},
+		{DropOldest, []string{"0", "debug", "error"}, 4},
+		{DropBelowLevel, []string{"0", "1", "2", "error"}, 3},
+	}
+	for _, tt := range tests {
+		r := &gateRecorder{gate: make(chan struct{})}
+		a := NewAsync(r, AsyncOptions{Size: 2, Policy: tt.policy, Level: mo.LevelError})
+		ctx := context.Background()
+
+		// The goroutine takes "0" and blocks, leaving room for 2 entries.
+		a.Log(ctx, mo.LevelInfo, "0", nil)
+		for {
+			a.mu.Lock()
+			busy := a.busy
+			a.mu.Unlock()
+			if busy != 0 {
+				break
+			}
+			runtime.Gosched()
+		}
+		for _, msg := range []string{"1", "2", "3", "4"} {
+			a.Log(ctx, mo.LevelInfo, msg, nil)
+		}
+		a.Log(ctx, mo.LevelDebug, "debug", nil)
+		if tt.policy == DropBelowLevel {
+			// The error entry waits for room in the queue.
+			logged := make(chan struct{})
+			go func() {
+				a.Log(ctx, mo.LevelError, "error", nil)
+				close(logged)
+			}()
+			close(r.gate)
+			<-logged
+		} else {
+			a.Log(ctx, mo.LevelError, "error", nil)
+			close(r.gate)
+		}
+
+		if err := a.Flush(); err != nil {
+			t.Fatal(err)
+		}
+		got := r.messages()
+		if len(got) != len(tt.want) {
+			t.Fatalf("policy %d: got %v, want %v", tt.policy, got, tt.want)
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Fatalf("policy %d: got %v, want %v", tt.policy, got, tt.want)
+			}
+		}
+		if a.Dropped() != tt.dropped {
+			t.Errorf("policy %d: dropped %d, want %d", tt.policy, a.Dropped(), tt.dropped)
+		}
+		if err := a.Close(); err != nil {
+			t.Fatal(err)
+		}
+		a.Log(ctx, mo.LevelError, "closed", nil)
+		if len(r.messages()) != len(tt.want) {
+			t.Errorf("policy %d: logged after Close", tt.policy)
+		}
+	}
+}
@@ -3,9 +3,7 @@ package record
 import (
 	"bytes"
 	"context"
-	"fmt"
 	"io"
-	"os"
 	"sync"
 
 	"github.com/mengdu/color"
@@ -18,117 +16,146 @@ const (
 	KeyCaller    = "caller"
 	KeyMessage   = "msg"
 	KeyLevel     = "level"
+	KeyLogger    = mo.LoggerKey
 )
 
+var (
+	levelColorsMu sync.RWMutex
+	levelColors   = map[mo.Level][2]color.ColorFn{}
+)
+
+// SetLevelColor sets the colors used by Console for the tag and the message of
+// entries at the given level, typically a level registered with mo.RegisterLevel.
+// A nil msg color leaves the message uncolored.
+func SetLevelColor(level mo.Level, tag, msg color.ColorFn) {
+	levelColorsMu.Lock()
+	defer levelColorsMu.Unlock()
+	levelColors[level] = [2]color.ColorFn{tag, msg}
+}
+
+// LevelColor returns the tag and message colors used by Console for the given level.
+// Levels without colors set by SetLevelColor use the colors of the closest built-in level below them.
+func LevelColor(level mo.Level) (tag, msg color.ColorFn) {
+	levelColorsMu.RLock()
+	c, ok := levelColors[level]
+	levelColorsMu.RUnlock()
+	if ok && c[0] != nil {
+		return c[0], c[1]
+	}
+
+	switch {
+	case level < mo.LevelInfo:
+		return color.BgGray().White(), color.Gray()
+	case level < mo.LevelWarn:
+		return color.BgBlue().White(), nil
+	case level < mo.LevelError:
+		return color.BgYellow().White(), color.Yellow()
+	default:
+		return color.BgRed().White(), color.Red()
+	}
+}
+
 // Console is a simple console logger.
+// It writes colored text encoded by ConsoleEncoder, entries at mo.LevelError and above go to Stderr.
+// FilterEmptyField, LevelType and NoColor are read on each call, Stdout and Stderr
+// must not be changed after the first log message.
 type Console struct {
 	FilterEmptyField bool
 	Stdout           io.Writer
 	Stderr           io.Writer
 	LevelType        string // "string", "abbr", "char"
-	mu               sync.Mutex
-	pool             *sync.Pool
+	NoColor          bool   // Disable colors, e.g. when writing to files
+	once             sync.Once
+	w                *Writer
 }
 
-// Log implements the Recorder interface.
-func (c *Console) Log(ctx context.Context, level mo.Level, msg string, kv []mo.Field) {
-	if c.pool == nil {
-		c.pool = &sync.Pool{
-			New: func() interface{} {
-				return new(bytes.Buffer)
-			},
-		}
-	}
-
-	buf := c.pool.Get().(*bytes.Buffer)
-	defer c.pool.Put(buf)
-	defer buf.Reset()
-
-	caller := ""
-	ts := ""
-	for _, v := range kv {
-		if v.Key() == KeyTimestamp {
-			ts = color.Dim().String(fmt.Sprintf("%v", v.Value()))
+func (c *Console) writer() *Writer {
+	c.once.Do(func() {
+		c.w = &Writer{
+			Encoder: consoleEncoder{c},
+			Sink:    c.Stdout,
+			ErrSink: c.Stderr,
 		}
-		if v.Key() == KeyCaller {
-			caller, _ = v.Value().(string)
-		}
-	}
+	})
+	return c.w
+}
 
-	if ts != "" {
-		buf.WriteString("[")
-		buf.WriteString(ts)
-		buf.WriteString("]")
-	}
+// consoleEncoder encodes entries with the current settings of the Console.
+type consoleEncoder struct {
+	c *Console
+}
 
-	tag := ""
-	if c.LevelType == "abbr" {
-		tag = level.Abbr()
-	} else if c.LevelType == "char" {
-		tag = level.Char()
-	} else {
-		tag = level.String()
+func (e consoleEncoder) Encode(buf *bytes.Buffer, entry mo.Entry) error {
+	enc := ConsoleEncoder{
+		FilterEmptyField: e.c.FilterEmptyField,
+		LevelType:        e.c.LevelType,
+		NoColor:          e.c.NoColor,
 	}
-	tag = fmt.Sprintf("[%s]", tag)
-	switch level {
-	case mo.LevelDebug:
-		tag = color.BgGray().White().String(tag)
-		msg = color.Gray().String(msg)
-	case mo.LevelInfo:
-		tag = color.BgBlue().White().String(tag)
-	case mo.LevelWarn:
-		tag = color.BgYellow().White().String(tag)
-		msg = color.Yellow().String(msg)
-	case mo.LevelError:
-		tag = color.BgRed().White().String(tag)
-		msg = color.Red().String(msg)
-	case mo.LevelFatal:
-		tag = color.BgRed().White().String(tag)
-		msg = color.Red().String(msg)
-	}
-
-	buf.WriteString(tag)
-	buf.WriteString(" ")
-	buf.WriteString(msg)
-
-	i := 0
-	for _, v := range kv {
-		if v.Key() == KeyTimestamp || v.Key() == KeyCaller {
-			continue
-		}
+	return enc.Encode(buf, entry)
+}
 
-		val := fmt.Sprint(v.Value())
-		// filter empty field
-		if c.FilterEmptyField && val == "" {
-			continue
-		}
+// Log implements the Recorder interface.
+func (c *Console) Log(ctx context.Context, level mo.Level, msg string, kv []mo.Field) {
+	c.writer().Log(ctx, level, msg, kv)
+}
 
-		if i > 0 {
-			buf.WriteString(", ")
-		} else {
-			buf.WriteString(" ")
-		}
+// ConsoleEncoder encodes entries as human readable text lines, such as
+// "[ts][INFO] message k1=v1, k2=v2 caller". Errors are written with their type
+// and fields, their causes and stack traces as indented blocks below the line.
+type ConsoleEncoder struct {
+	FilterEmptyField bool   // Omit fields with empty values
+	LevelType        string // "string", "abbr", "char"
+	NoColor          bool   // Disable colors, e.g. when writing to files
+}
 
-		buf.WriteString(color.Gray().String(v.Key()))
-		buf.WriteString("=")
-		buf.WriteString(val)
-		i++
+// Encode implements the Encoder interface.
+func (e *ConsoleEncoder) Encode(buf *bytes.Buffer, entry mo.Entry) error {
+	f := mo.TextFormat{FilterEmptyField: e.FilterEmptyField, LevelTag: e.levelTag}
+	if !e.NoColor {
+		f.Paint = paintText
 	}
-	if caller != "" {
-		buf.WriteString(" ")
-		buf.WriteString(color.Gray().Dim().String(caller))
+	f.Write(buf, entry)
+	return nil
+}
+
+func (e *ConsoleEncoder) levelTag(level mo.Level) string {
+	switch e.LevelType {
+	case "abbr":
+		return level.Abbr()
+	case "char":
+		return level.Char()
+	default:
+		return level.String()
 	}
-	buf.WriteByte('\n')
+}
 
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	if level >= mo.LevelError {
-		if _, err := c.Stderr.Write(buf.Bytes()); err != nil {
-			fmt.Fprintf(os.Stderr, "write failed: %v\n", err)
-		}
-		return
+// paintText colors the parts of the lines written by ConsoleEncoder.
+func paintText(part mo.TextPart, level mo.Level, s string) string {
+	var c color.ColorFn
+	switch part {
+	case mo.TextTimestamp:
+		c = color.Dim()
+	case mo.TextLevel:
+		c, _ = LevelColor(level)
+	case mo.TextMessage:
+		_, c = LevelColor(level)
+	case mo.TextKey, mo.TextDetail:
+		c = color.Gray()
+	case mo.TextCaller:
+		c = color.Gray().Dim()
 	}
-	if _, err := c.Stdout.Write(buf.Bytes()); err != nil {
-		fmt.Fprintf(os.Stderr, "write failed: %v\n", err)
+	if c == nil {
+		return s
 	}
+	return c.String(s)
+}
+
+// Flush flushes Stdout and Stderr if they buffer writes.
+func (c *Console) Flush() error {
+	return c.writer().Flush()
+}
+
+// Close flushes Stdout and Stderr and closes them, unless they are os.Stdout or os.Stderr.
+func (c *Console) Close() error {
+	return c.writer().Close()
 }
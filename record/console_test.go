@@ -0,0 +1,153 @@
+package record
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mengdu/color"
+	"github.com/mengdu/mo"
+)
+
+// sameColor reports whether the colors produce the same escape codes.
+func sameColor(a, b color.ColorFn) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	aOpen, aClose, _, _ := a("x")
+	bOpen, bClose, _, _ := b("x")
+	return fmt.Sprint(aOpen, aClose) == fmt.Sprint(bOpen, bClose)
+}
+
+func TestLevelColor(t *testing.T) {
+	const levelNotice = mo.LevelInfo + 2
+	SetLevelColor(levelNotice, color.BgCyan().White(), color.Cyan())
+	tag, msg := LevelColor(levelNotice)
+	if !sameColor(tag, color.BgCyan().White()) || !sameColor(msg, color.Cyan()) {
+		t.Error("colors set by SetLevelColor not used")
+	}
+
+	// Levels without colors use the colors of the closest built-in level below them.
+	tests := []struct {
+		level mo.Level
+		same  mo.Level
+	}{
+		{mo.LevelDebug - 4, mo.LevelDebug},
+		{mo.LevelInfo + 1, mo.LevelInfo},
+		{mo.LevelWarn + 2, mo.LevelWarn},
+		{mo.LevelError + 1, mo.LevelError},
+		{mo.LevelFatal + 10, mo.LevelError},
+	}
+	for _, tt := range tests {
+		tag, msg := LevelColor(tt.level)
+		wantTag, wantMsg := LevelColor(tt.same)
+		if !sameColor(tag, wantTag) || !sameColor(msg, wantMsg) {
+			t.Errorf("level %d does not use the colors of level %d", tt.level, tt.same)
+		}
+	}
+	if _, msg := LevelColor(mo.LevelInfo); msg != nil {
+		t.Error("info messages should be uncolored")
+	}
+}
+
+// codeError is an error with a code field.
+type codeError struct {
+	err error
+}
+
+func (e codeError) Error() string         { return "query: " + e.err.Error() }
+func (e codeError) Unwrap() error         { return e.err }
+func (e codeError) LogFields() []mo.Field { return []mo.Field{mo.Int("code", 42)} }
+
+func encodeConsole(t *testing.T, e *ConsoleEncoder, entry mo.Entry) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := e.Encode(&buf, entry); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestConsoleEncoder(t *testing.T) {
+	ts := time.Date(2024, 10, 18, 9, 30, 0, 0, time.UTC)
+	entry := mo.Entry{Level: mo.LevelWarn, Message: "slow query", Fields: []mo.Field{
+		mo.Time(KeyTimestamp, ts),
+		mo.String("table", "users"),
+		mo.String("empty", ""),
+		mo.Duration("took", 1500*time.Millisecond),
+		mo.String(KeyCaller, "db/query.go:42"),
+	}}
+
+	tests := []struct {
+		enc  ConsoleEncoder
+		want string
+	}{
+		{ConsoleEncoder{NoColor: true}, "[2024-10-18T09:30:00Z][WARN] slow query table=users, empty=, took=1.5s db/query.go:42\n"},
+		{ConsoleEncoder{NoColor: true, LevelType: "abbr", FilterEmptyField: true}, "[2024-10-18T09:30:00Z][WRN] slow query table=users, took=1.5s db/query.go:42\n"},
+		{ConsoleEncoder{NoColor: true, LevelType: "char"}, "[2024-10-18T09:30:00Z][W] slow query table=users, empty=, took=1.5s db/query.go:42\n"},
+	}
+	for _, tt := range tests {
+		if got := encodeConsole(t, &tt.enc, entry); got != tt.want {
+			t.Errorf("%+v:\ngot  %q\nwant %q", tt.enc, got, tt.want)
+		}
+	}
+
+	colored := encodeConsole(t, &ConsoleEncoder{}, entry)
+	tag, msg := LevelColor(mo.LevelWarn)
+	if !strings.Contains(colored, tag.String("[WARN]")) || !strings.Contains(colored, msg.String("slow query")) {
+		t.Errorf("level colors missing in %q", colored)
+	}
+}
+
+func TestConsoleEncoder_Error(t *testing.T) {
+	err := fmt.Errorf("load: %w", codeError{errors.New("timeout")})
+	got := encodeConsole(t, &ConsoleEncoder{NoColor: true}, mo.Entry{Level: mo.LevelError, Message: "failed", Fields: []mo.Field{mo.Err(err)}})
+	want := "[ERROR] failed error=load: query: timeout [*fmt.wrapError], error.code=42\n" +
+		"    caused by [record.codeError]: query: timeout\n" +
+		"    caused by [*errors.errorString]: timeout\n"
+	if got != want {
+		t.Errorf("got\n%swant\n%s", got, want)
+	}
+}
+
+func TestConsole(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	c := &Console{Stdout: &stdout, Stderr: &stderr, NoColor: true}
+	ctx := context.Background()
+	c.Log(ctx, mo.LevelInfo, "a", []mo.Field{mo.String("k", "")})
+	c.Log(ctx, mo.LevelError, "b", nil)
+
+	// Settings changed after the first message are used.
+	c.LevelType = "abbr"
+	c.FilterEmptyField = true
+	c.Log(ctx, mo.LevelInfo, "c", []mo.Field{mo.String("k", "")})
+
+	if got := stdout.String(); got != "[INFO] a k=\n[INF] c\n" {
+		t.Errorf("Stdout got %q", got)
+	}
+	if got := stderr.String(); got != "[ERROR] b\n" {
+		t.Errorf("Stderr got %q", got)
+	}
+}
+
+func TestConsoleEncoder_Stacktrace(t *testing.T) {
+	st := mo.Stack()
+	got := encodeConsole(t, &ConsoleEncoder{NoColor: true}, mo.Entry{Level: mo.LevelError, Message: "failed", Fields: []mo.Field{st}})
+	lines := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+	if lines[0] != "[ERROR] failed" {
+		t.Errorf("stack trace written as a field: %q", lines[0])
+	}
+	want := strings.Split(st.Stacktrace().String(), "\n")
+	if len(lines) != len(want)+1 {
+		t.Fatalf("got %d stack lines, want %d", len(lines)-1, len(want))
+	}
+	for i, line := range want {
+		if lines[i+1] != "    "+line {
+			t.Errorf("line %d = %q, want it indented", i+1, lines[i+1])
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package record
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mengdu/mo"
+)
+
+// Keys of the fields added by Dedup to its summaries.
+const (
+	KeyRepeated = "repeated"
+	KeyFirst    = "first"
+	KeyLast     = "last"
+)
+
+// Dedup is a recorder that collapses consecutive identical log messages written to Recorder.
+// Messages are identical when their level, message and fields are equal, ignoring the
+// KeyTimestamp and KeyCaller fields and stack traces.
+//
+// The first message of a run is passed to Recorder and the repeats are suppressed. When the
+// run ends, on Flush and Close, and at most Window after the first suppressed repeat, Dedup
+// logs a summary with the fields of the repeated message and the KeyRepeated, KeyFirst and
+// KeyLast fields, such as "last message repeated 1532 times". Recorder is called without
+// holding the lock of the Dedup, so a summary may be recorded after a message logged
+// concurrently with the one that ended the run.
+//
+// The fields of a Dedup must not be changed after the first log message.
+type Dedup struct {
+	Recorder mo.Recorder
+	Window   time.Duration // Maximum delay of summaries, 0 waits until the run ends
+
+	mu      sync.Mutex
+	sig     string // Signature of the last message, see signature
+	ctx     context.Context
+	level   mo.Level
+	msg     string
+	kv      []mo.Field
+	repeats int       // Number of suppressed repeats of the last message
+	first   time.Time // Time of the first suppressed repeat
+	last    time.Time // Time of the last suppressed repeat
+	timer   *time.Timer
+	run     uint64 // Incremented when a summary is logged, to ignore stale timers
+}
+
+// Log implements the Recorder interface.
+func (d *Dedup) Log(ctx context.Context, level mo.Level, msg string, kv []mo.Field) {
+	buf := bufPool.Get().(*bytes.Buffer)
+	defer bufPool.Put(buf)
+	defer buf.Reset()
+	signature(buf, level, msg, kv)
+
+	d.mu.Lock()
+	if d.kv != nil && string(buf.Bytes()) == d.sig {
+		now := time.Now()
+		if d.repeats == 0 {
+			d.first = now
+			if d.Window > 0 {
+				run := d.run
+				d.timer = time.AfterFunc(d.Window, func() {
+					d.mu.Lock()
+					var s *dedupSummary
+					if d.run == run {
+						s = d.summarize()
+					}
+					d.mu.Unlock()
+					s.log(d.Recorder)
+				})
+			}
+		}
+		d.ctx = ctx
+		d.repeats++
+		d.last = now
+		d.mu.Unlock()
+		return
+	}
+
+	s := d.summarize()
+	d.sig = buf.String()
+	d.ctx, d.level, d.msg = ctx, level, msg
+	d.kv = append(make([]mo.Field, 0, len(kv)), kv...)
+	d.mu.Unlock()
+
+	// Recorder is called without mu held, so it may take its time or log to d itself.
+	s.log(d.Recorder)
+	d.Recorder.Log(ctx, level, msg, kv)
+}
+
+// dedupSummary is a summary of suppressed repeats, logged after mu is released.
+type dedupSummary struct {
+	ctx   context.Context
+	level mo.Level
+	msg   string
+	kv    []mo.Field
+}
+
+// log logs the summary to r, if any.
+func (s *dedupSummary) log(r mo.Recorder) {
+	if s != nil {
+		r.Log(s.ctx, s.level, s.msg, s.kv)
+	}
+}
+
+// summarize returns the summary of the suppressed repeats, or nil if there are none,
+// and starts a new run. It must be called with mu held.
+func (d *Dedup) summarize() *dedupSummary {
+	if d.repeats == 0 {
+		return nil
+	}
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	kv := make([]mo.Field, 0, len(d.kv)+3)
+	kv = append(kv, d.kv...)
+	kv = append(kv, mo.Int(KeyRepeated, d.repeats), mo.Time(KeyFirst, d.first), mo.Time(KeyLast, d.last))
+	msg := "last message repeated " + strconv.Itoa(d.repeats) + " times"
+	d.repeats = 0
+	d.run++
+	return &dedupSummary{ctx: d.ctx, level: d.level, msg: msg, kv: kv}
+}
+
+// signature writes the level, message and fields compared by Dedup to buf.
+func signature(buf *bytes.Buffer, level mo.Level, msg string, kv []mo.Field) {
+	buf.WriteString(strconv.Itoa(int(level)))
+	buf.WriteByte(0)
+	buf.WriteString(msg)
+	for _, v := range kv {
+		if v.Key() == KeyTimestamp || v.Key() == KeyCaller || v.Type() == mo.StackType {
+			continue
+		}
+		buf.WriteByte(0)
+		buf.WriteString(v.Key())
+		buf.WriteByte('=')
+		buf.WriteString(v.Text())
+	}
+}
+
+// Flush logs the summary of the suppressed repeats, if any, and flushes Recorder.
+func (d *Dedup) Flush() error {
+	d.mu.Lock()
+	s := d.summarize()
+	d.mu.Unlock()
+	s.log(d.Recorder)
+	return mo.FlushRecorder(d.Recorder)
+}
+
+// Close logs the summary of the suppressed repeats, if any, and closes Recorder.
+func (d *Dedup) Close() error {
+	d.mu.Lock()
+	s := d.summarize()
+	d.mu.Unlock()
+	s.log(d.Recorder)
+	return mo.CloseRecorder(d.Recorder)
+}
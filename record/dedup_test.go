@@ -0,0 +1,127 @@
+package record
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mengdu/mo"
+)
+
+// entryRecorder records the log messages.
+type entryRecorder struct {
+	entries []mo.Entry
+}
+
+func (r *entryRecorder) Log(ctx context.Context, level mo.Level, msg string, kv []mo.Field) {
+	r.entries = append(r.entries, mo.Entry{Level: level, Message: msg, Fields: kv})
+}
+
+// chanRecorder sends the messages to the channel.
+type chanRecorder chan string
+
+func (r chanRecorder) Log(ctx context.Context, level mo.Level, msg string, kv []mo.Field) {
+	r <- msg
+}
+
+func TestDedup(t *testing.T) {
+	r := &entryRecorder{}
+	d := &Dedup{Recorder: r}
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		d.Log(ctx, mo.LevelError, "dial failed", []mo.Field{mo.String("host", "db"), mo.Int(KeyTimestamp, i)})
+	}
+	d.Log(ctx, mo.LevelError, "dial failed", []mo.Field{mo.String("host", "cache")})
+	d.Log(ctx, mo.LevelError, "dial failed", []mo.Field{mo.String("host", "cache")})
+	if err := d.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"dial failed",
+		"last message repeated 4 times",
+		"dial failed",
+		"last message repeated 1 times",
+	}
+	if len(r.entries) != len(want) {
+		t.Fatalf("got %d entries: %v", len(r.entries), r.entries)
+	}
+	for i, e := range r.entries {
+		if e.Message != want[i] {
+			t.Errorf("entry %d = %q, want %q", i, e.Message, want[i])
+		}
+	}
+
+	summary := r.entries[1].Fields
+	if len(summary) != 5 || summary[0].Str() != "db" || summary[2].Int64() != 4 {
+		t.Errorf("unexpected summary fields %v", summary)
+	}
+	if first, last := summary[3].Time(), summary[4].Time(); last.Before(first) {
+		t.Errorf("last %v before first %v", last, first)
+	}
+}
+
+func TestDedup_Window(t *testing.T) {
+	r := make(chanRecorder, 2)
+	d := &Dedup{Recorder: r, Window: 10 * time.Millisecond}
+	for i := 0; i < 3; i++ {
+		d.Log(context.Background(), mo.LevelWarn, "flapping", nil)
+	}
+
+	<-r
+	select {
+	case msg := <-r:
+		if msg != "last message repeated 2 times" {
+			t.Fatalf("unexpected summary %q", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("summary not logged after the window")
+	}
+}
+
+// flushingRecorder flushes the Dedup writing to it on each message, which
+// deadlocks if the Dedup calls it with its lock held.
+type flushingRecorder struct {
+	d    *Dedup
+	msgs chan string
+}
+
+func (r *flushingRecorder) Log(ctx context.Context, level mo.Level, msg string, kv []mo.Field) {
+	r.d.Flush()
+	r.msgs <- msg
+}
+
+func TestDedup_Unlocked(t *testing.T) {
+	r := &flushingRecorder{msgs: make(chan string, 10)}
+	d := &Dedup{Recorder: r, Window: 10 * time.Millisecond}
+	r.d = d
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ctx := context.Background()
+		d.Log(ctx, mo.LevelWarn, "a", nil)
+		d.Log(ctx, mo.LevelWarn, "a", nil)
+		d.Log(ctx, mo.LevelWarn, "b", nil)
+		d.Log(ctx, mo.LevelWarn, "b", nil)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Dedup called Recorder with its lock held")
+	}
+
+	// The last repeat is summarized by the timer, also without the lock held.
+	want := []string{"a", "last message repeated 1 times", "b", "last message repeated 1 times"}
+	for i, w := range want {
+		select {
+		case msg := <-r.msgs:
+			if msg != w {
+				t.Errorf("message %d = %q, want %q", i, msg, w)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("message %d %q not logged", i, w)
+		}
+	}
+}
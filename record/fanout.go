@@ -0,0 +1,152 @@
+package record
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/mengdu/mo"
+)
+
+// ErrTimeout is reported by FanOut for recorders that did not return within FanOut.Timeout.
+var ErrTimeout = errors.New("record: recorder timed out")
+
+// FanOut is a recorder that records log messages to all of Recorders, like mo.Combine,
+// while isolating them from each other: a panic in a recorder is recovered and reported
+// to ErrorHandler, and the other recorders still receive the message.
+//
+// With Parallel set, the recorders are called concurrently and Log returns once all of them
+// have returned, or after Timeout if it is positive. Recorders still running after Timeout are
+// reported with ErrTimeout and left to finish in the background; they are skipped, and miss
+// the log messages, until that call returns, so a hanging recorder neither piles up goroutines
+// nor delays every log message by Timeout. Recorders must not modify the key-value pairs
+// passed to them.
+//
+// The fields of a FanOut must not be changed after the first log message.
+type FanOut struct {
+	Recorders []mo.Recorder
+	// ErrorHandler is called with the recorder and the error when a recorder panics,
+	// times out, or fails to flush or close. It defaults to writing the error to os.Stderr.
+	ErrorHandler func(r mo.Recorder, err error)
+	Parallel     bool
+	Timeout      time.Duration
+
+	mu      sync.Mutex
+	pending []int  // Number of running parallel calls of each recorder
+	stalled []bool // Recorders timed out and skipped until one of their calls returns
+}
+
+// Log implements the Recorder interface.
+func (f *FanOut) Log(ctx context.Context, level mo.Level, msg string, kv []mo.Field) {
+	if !f.Parallel {
+		for _, r := range f.Recorders {
+			f.call(r, func() { r.Log(ctx, level, msg, kv) })
+		}
+		return
+	}
+
+	// Buffered so that recorders returning after the timeout do not block.
+	done := make(chan int, len(f.Recorders))
+	finished := make([]bool, len(f.Recorders))
+	calls := 0
+	f.mu.Lock()
+	if f.pending == nil {
+		f.pending = make([]int, len(f.Recorders))
+		f.stalled = make([]bool, len(f.Recorders))
+	}
+	for i, r := range f.Recorders {
+		if f.stalled[i] {
+			finished[i] = true
+			continue
+		}
+		f.pending[i]++
+		calls++
+		go func(i int, r mo.Recorder) {
+			f.call(r, func() { r.Log(ctx, level, msg, kv) })
+			f.mu.Lock()
+			f.pending[i]--
+			f.stalled[i] = false
+			f.mu.Unlock()
+			done <- i
+		}(i, r)
+	}
+	f.mu.Unlock()
+
+	if f.Timeout <= 0 {
+		for ; calls > 0; calls-- {
+			<-done
+		}
+		return
+	}
+
+	timer := time.NewTimer(f.Timeout)
+	defer timer.Stop()
+	for ; calls > 0; calls-- {
+		select {
+		case i := <-done:
+			finished[i] = true
+		case <-timer.C:
+			f.mu.Lock()
+			for i, ok := range finished {
+				// A recorder that returned since the timeout is not stalled.
+				if !ok && f.pending[i] > 0 {
+					f.stalled[i] = true
+				}
+			}
+			f.mu.Unlock()
+			for i, ok := range finished {
+				if !ok {
+					f.handle(f.Recorders[i], ErrTimeout)
+				}
+			}
+			return
+		}
+	}
+}
+
+// call calls fn, reporting a panic as an error of r.
+func (f *FanOut) call(r mo.Recorder, fn func()) {
+	defer func() {
+		if p := recover(); p != nil {
+			f.handle(r, fmt.Errorf("record: %T panicked: %v", r, p))
+		}
+	}()
+	fn()
+}
+
+func (f *FanOut) handle(r mo.Recorder, err error) {
+	if f.ErrorHandler != nil {
+		f.ErrorHandler(r, err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "log failed: %v\n", err)
+}
+
+// Flush flushes all recorders, reports their errors and panics to ErrorHandler and returns the first error.
+func (f *FanOut) Flush() error {
+	return f.each(mo.FlushRecorder)
+}
+
+// Close closes all recorders, reports their errors and panics to ErrorHandler and returns the first error.
+func (f *FanOut) Close() error {
+	return f.each(mo.CloseRecorder)
+}
+
+func (f *FanOut) each(fn func(mo.Recorder) error) error {
+	var first error
+	for _, r := range f.Recorders {
+		r := r
+		var err error
+		f.call(r, func() { err = fn(r) })
+		if err != nil {
+			f.handle(r, err)
+			if first == nil {
+				first = err
+			}
+		}
+	}
+	return first
+}
@@ -0,0 +1,135 @@
+package record
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mengdu/mo"
+)
+
+// panicRecorder panics on every log message.
+type panicRecorder struct{}
+
+func (panicRecorder) Log(ctx context.Context, level mo.Level, msg string, kv []mo.Field) {
+	panic("boom")
+}
+
+// slowRecorder blocks every log message until release is closed.
+type slowRecorder struct {
+	release chan struct{}
+}
+
+func (r slowRecorder) Log(ctx context.Context, level mo.Level, msg string, kv []mo.Field) {
+	<-r.release
+}
+
+func TestFanOut(t *testing.T) {
+	var mu sync.Mutex
+	var errs []error
+	healthy := &gateRecorder{gate: make(chan struct{})}
+	close(healthy.gate)
+	slow := slowRecorder{release: make(chan struct{})}
+	defer close(slow.release)
+
+	for _, parallel := range []bool{false, true} {
+		errs = nil
+		f := &FanOut{
+			Recorders: []mo.Recorder{panicRecorder{}, healthy},
+			ErrorHandler: func(r mo.Recorder, err error) {
+				mu.Lock()
+				defer mu.Unlock()
+				errs = append(errs, err)
+			},
+			Parallel: parallel,
+			Timeout:  10 * time.Millisecond,
+		}
+		if parallel {
+			f.Recorders = append(f.Recorders, slow)
+		}
+		f.Log(context.Background(), mo.LevelInfo, "msg", nil)
+
+		mu.Lock()
+		want := 1
+		if parallel {
+			want = 2
+		}
+		if len(errs) != want {
+			t.Fatalf("parallel %v: got errors %v", parallel, errs)
+		}
+		if parallel && !errors.Is(errs[0], ErrTimeout) && !errors.Is(errs[1], ErrTimeout) {
+			t.Errorf("slow recorder not reported: %v", errs)
+		}
+		mu.Unlock()
+	}
+	if got := healthy.messages(); len(got) != 2 {
+		t.Fatalf("healthy recorder got %v", got)
+	}
+}
+
+// stallRecorder counts the log messages and blocks them until release is closed.
+type stallRecorder struct {
+	calls   int32
+	release chan struct{}
+}
+
+func (r *stallRecorder) Log(ctx context.Context, level mo.Level, msg string, kv []mo.Field) {
+	atomic.AddInt32(&r.calls, 1)
+	<-r.release
+}
+
+func TestFanOut_Stalled(t *testing.T) {
+	var timeouts int32
+	stall := &stallRecorder{release: make(chan struct{})}
+	healthy := &gateRecorder{gate: make(chan struct{})}
+	close(healthy.gate)
+	f := &FanOut{
+		Recorders: []mo.Recorder{stall, healthy},
+		ErrorHandler: func(r mo.Recorder, err error) {
+			if errors.Is(err, ErrTimeout) {
+				atomic.AddInt32(&timeouts, 1)
+			}
+		},
+		Parallel: true,
+		Timeout:  20 * time.Millisecond,
+	}
+	ctx := context.Background()
+
+	f.Log(ctx, mo.LevelInfo, "msg", nil)
+	// The stalled recorder is skipped instead of timing out again.
+	for i := 0; i < 5; i++ {
+		f.Log(ctx, mo.LevelInfo, "msg", nil)
+	}
+	if n := atomic.LoadInt32(&timeouts); n != 1 {
+		t.Errorf("got %d timeouts, want 1", n)
+	}
+	if n := atomic.LoadInt32(&stall.calls); n != 1 {
+		t.Errorf("stalled recorder called %d times, want 1", n)
+	}
+
+	// It receives messages again once its call returns.
+	close(stall.release)
+	deadline := time.Now().Add(time.Second)
+	for {
+		f.mu.Lock()
+		stalled := f.stalled[0]
+		f.mu.Unlock()
+		if !stalled {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("recorder still stalled after returning")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	f.Log(ctx, mo.LevelInfo, "msg", nil)
+	if n := atomic.LoadInt32(&stall.calls); n != 2 {
+		t.Errorf("recovered recorder called %d times, want 2", n)
+	}
+	if got := healthy.messages(); len(got) != 7 {
+		t.Errorf("healthy recorder got %d messages, want 7", len(got))
+	}
+}
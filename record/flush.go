@@ -0,0 +1,67 @@
+package record
+
+import (
+	"io"
+	"os"
+
+	"github.com/mengdu/mo"
+)
+
+type syncer interface {
+	Sync() error
+}
+
+// flushWriters flushes the writers implementing mo.Flusher, such as *bufio.Writer,
+// syncs those implementing Sync() error, such as *os.File, except os.Stdout and
+// os.Stderr, and returns the first error.
+func flushWriters(ws ...io.Writer) error {
+	var first error
+	for i, w := range ws {
+		if w == nil || seen(ws[:i], w) {
+			continue
+		}
+		var err error
+		switch v := w.(type) {
+		case mo.Flusher:
+			err = v.Flush()
+		case syncer:
+			if !isStdio(w) {
+				err = v.Sync()
+			}
+		}
+		if err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// closeWriters flushes the writers and closes those implementing io.Closer,
+// except os.Stdout and os.Stderr, and returns the first error.
+func closeWriters(ws ...io.Writer) error {
+	first := flushWriters(ws...)
+	for i, w := range ws {
+		if w == nil || isStdio(w) || seen(ws[:i], w) {
+			continue
+		}
+		if c, ok := w.(io.Closer); ok {
+			if err := c.Close(); err != nil && first == nil {
+				first = err
+			}
+		}
+	}
+	return first
+}
+
+func seen(ws []io.Writer, w io.Writer) bool {
+	for _, v := range ws {
+		if v == w {
+			return true
+		}
+	}
+	return false
+}
+
+func isStdio(w io.Writer) bool {
+	return w == io.Writer(os.Stdout) || w == io.Writer(os.Stderr)
+}
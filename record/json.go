@@ -1,30 +1,173 @@
 package record
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"math"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
+	"unicode/utf8"
 
 	"github.com/mengdu/mo"
 )
 
-// Key constants for structured logging
+// JSON is a recorder that writes log messages as JSON lines.
 type JSON struct {
+	// Encoder encodes the log messages with encoding/json. If nil, the log messages
+	// are encoded without reflection for typed fields and written to Output.
 	Encoder *json.Encoder
-	mu      sync.Mutex
+	// Output is the destination of the log messages. It is flushed and closed
+	// with the recorder when it implements mo.Flusher or io.Closer.
+	Output io.Writer
+	// StackFrames encodes stack traces as arrays of {"function","file","line"}
+	// objects rather than as strings.
+	StackFrames bool
+	mu          sync.Mutex // Guards Encoder
+	once        sync.Once
+	w           *Writer
 }
 
 // Log implements the Recorder interface.
 func (l *JSON) Log(ctx context.Context, level mo.Level, msg string, kv []mo.Field) {
+	if l.Encoder != nil {
+		l.encode(level, msg, kv)
+		return
+	}
+	l.writer().Log(ctx, level, msg, kv)
+}
+
+func (l *JSON) writer() *Writer {
+	l.once.Do(func() {
+		l.w = &Writer{Encoder: JSONEncoder{StackFrames: l.StackFrames}, Sink: l.Output}
+	})
+	return l.w
+}
+
+// JSONEncoder encodes entries as JSON lines with the level and message under the
+// KeyLevel and KeyMessage keys followed by the fields. Fields named KeyLevel or
+// KeyMessage are written under "fields.level" and "fields.msg" so they don't
+// duplicate those keys. Typed fields are encoded without reflection; AnyType
+// values are encoded with encoding/json.
+type JSONEncoder struct {
+	// StackFrames encodes stack traces as arrays of {"function","file","line"}
+	// objects instead of strings.
+	StackFrames bool
+}
+
+// Encode implements the Encoder interface.
+func (e JSONEncoder) Encode(buf *bytes.Buffer, entry mo.Entry) error {
+	buf.WriteByte('{')
+	writeJSONString(buf, KeyLevel)
+	buf.WriteByte(':')
+	writeJSONString(buf, strings.ToLower(entry.Level.String()))
+	buf.WriteByte(',')
+	writeJSONString(buf, KeyMessage)
+	buf.WriteByte(':')
+	writeJSONString(buf, entry.Message)
+	for _, v := range entry.Fields {
+		key := fieldKey(v.Key())
+		buf.WriteByte(',')
+		writeJSONString(buf, key)
+		buf.WriteByte(':')
+		if v.Type() == mo.ErrorType {
+			e.writeError(buf, key, v.Err())
+			continue
+		}
+		e.writeValue(buf, v)
+	}
+	buf.WriteString("}\n")
+	return nil
+}
+
+// fieldKey returns the JSON key of a field, prefixed with "fields." when it
+// collides with the level or message key.
+func fieldKey(key string) string {
+	if key == KeyLevel || key == KeyMessage {
+		return "fields." + key
+	}
+	return key
+}
+
+// writeError writes the message of err followed by its type, causes and fields
+// under the "<key>.type", "<key>.causes" and "<key>.<field>" keys.
+func (e JSONEncoder) writeError(buf *bytes.Buffer, key string, err error) {
+	d := mo.DescribeError(err)
+	writeJSONString(buf, d.Message)
+	buf.WriteByte(',')
+	writeJSONString(buf, key+".type")
+	buf.WriteByte(':')
+	writeJSONString(buf, d.Type)
+	if len(d.Causes) > 0 {
+		buf.WriteByte(',')
+		writeJSONString(buf, key+".causes")
+		buf.WriteString(":[")
+		for i, c := range d.Causes {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			buf.WriteString(`{"message":`)
+			writeJSONString(buf, c.Message)
+			buf.WriteString(`,"type":`)
+			writeJSONString(buf, c.Type)
+			buf.WriteByte('}')
+		}
+		buf.WriteByte(']')
+	}
+	for _, f := range d.Fields {
+		buf.WriteByte(',')
+		writeJSONString(buf, key+"."+f.Key())
+		buf.WriteByte(':')
+		e.writeValue(buf, f)
+	}
+}
+
+// encode writes the log message with Encoder.
+func (l *JSON) encode(level mo.Level, msg string, kv []mo.Field) {
 	line := make(map[string]interface{}, len(kv)+2)
 	line[KeyLevel] = strings.ToLower(level.String())
 	line[KeyMessage] = msg
 
 	for _, v := range kv {
-		line[v.Key()] = v.Value()
+		key := fieldKey(v.Key())
+		if v.Type() == mo.StackType {
+			// Encode the functions and lines rather than the program counters.
+			if !l.StackFrames {
+				line[key] = v.Stacktrace().String()
+				continue
+			}
+			frames := v.Stacktrace().Frames()
+			objs := make([]map[string]interface{}, len(frames))
+			for i, frame := range frames {
+				objs[i] = map[string]interface{}{"function": frame.Function, "file": frame.File, "line": frame.Line}
+			}
+			line[key] = objs
+			continue
+		}
+		if v.Type() != mo.ErrorType {
+			line[key] = jsonValue(v)
+			continue
+		}
+
+		// Errors usually have no exported fields, encode their details instead.
+		d := mo.DescribeError(v.Err())
+		line[key] = d.Message
+		line[key+".type"] = d.Type
+		if len(d.Causes) > 0 {
+			causes := make([]map[string]string, len(d.Causes))
+			for i, c := range d.Causes {
+				causes[i] = map[string]string{"message": c.Message, "type": c.Type}
+			}
+			line[key+".causes"] = causes
+		}
+		for _, f := range d.Fields {
+			line[key+"."+f.Key()] = jsonValue(f)
+		}
 	}
 
 	l.mu.Lock()
@@ -34,3 +177,142 @@ func (l *JSON) Log(ctx context.Context, level mo.Level, msg string, kv []mo.Fiel
 		fmt.Fprintf(os.Stderr, "write failed: %v\n", err)
 	}
 }
+
+// jsonValue returns the value of the field to encode with encoding/json, encoding
+// durations and the floats JSON can't represent as strings like JSONEncoder does.
+func jsonValue(v mo.Field) interface{} {
+	switch v.Type() {
+	case mo.Float64Type, mo.Float32Type:
+		f := v.Float64()
+		switch {
+		case math.IsNaN(f):
+			return "NaN"
+		case math.IsInf(f, 1):
+			return "+Inf"
+		case math.IsInf(f, -1):
+			return "-Inf"
+		}
+	case mo.DurationType:
+		return v.Duration().String()
+	}
+	return v.Value()
+}
+
+// Flush flushes Output if it buffers writes.
+func (l *JSON) Flush() error {
+	return l.writer().Flush()
+}
+
+// Close flushes and closes Output, unless it is os.Stdout or os.Stderr.
+func (l *JSON) Close() error {
+	return l.writer().Close()
+}
+
+// writeValue writes the field value as JSON. Only AnyType values are encoded with encoding/json.
+func (e JSONEncoder) writeValue(buf *bytes.Buffer, v mo.Field) {
+	var tmp [64]byte
+	switch v.Type() {
+	case mo.StringType:
+		writeJSONString(buf, v.Str())
+	case mo.Int64Type:
+		buf.Write(strconv.AppendInt(tmp[:0], v.Int64(), 10))
+	case mo.Uint64Type:
+		buf.Write(strconv.AppendUint(tmp[:0], v.Uint64(), 10))
+	case mo.Float64Type, mo.Float32Type:
+		f := v.Float64()
+		switch {
+		case math.IsNaN(f):
+			buf.WriteString(`"NaN"`)
+		case math.IsInf(f, 1):
+			buf.WriteString(`"+Inf"`)
+		case math.IsInf(f, -1):
+			buf.WriteString(`"-Inf"`)
+		default:
+			buf.Write(v.AppendValue(tmp[:0]))
+		}
+	case mo.BoolType:
+		buf.Write(strconv.AppendBool(tmp[:0], v.Bool()))
+	case mo.DurationType:
+		writeJSONString(buf, v.Duration().String())
+	case mo.TimeType:
+		buf.WriteByte('"')
+		buf.Write(v.Time().AppendFormat(tmp[:0], time.RFC3339Nano))
+		buf.WriteByte('"')
+	case mo.ErrorType:
+		writeJSONString(buf, v.Err().Error())
+	case mo.CallerType:
+		writeJSONString(buf, string(v.AppendValue(tmp[:0])))
+	case mo.StackType:
+		if !e.StackFrames {
+			writeJSONString(buf, v.Stacktrace().String())
+			return
+		}
+		buf.WriteByte('[')
+		for i, frame := range v.Stacktrace().Frames() {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			buf.WriteString(`{"function":`)
+			writeJSONString(buf, frame.Function)
+			buf.WriteString(`,"file":`)
+			writeJSONString(buf, frame.File)
+			buf.WriteString(`,"line":`)
+			buf.Write(strconv.AppendInt(tmp[:0], int64(frame.Line), 10))
+			buf.WriteByte('}')
+		}
+		buf.WriteByte(']')
+	default:
+		b, err := json.Marshal(v.Any())
+		if err != nil {
+			writeJSONString(buf, fmt.Sprint(v.Any()))
+			return
+		}
+		buf.Write(b)
+	}
+}
+
+const hex = "0123456789abcdef"
+
+// writeJSONString writes s as a quoted JSON string, replacing invalid UTF-8 with U+FFFD.
+func writeJSONString(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+	start := 0
+	for i := 0; i < len(s); {
+		if b := s[i]; b < utf8.RuneSelf {
+			if b >= 0x20 && b != '"' && b != '\\' {
+				i++
+				continue
+			}
+			buf.WriteString(s[start:i])
+			switch b {
+			case '"', '\\':
+				buf.WriteByte('\\')
+				buf.WriteByte(b)
+			case '\n':
+				buf.WriteString(`\n`)
+			case '\r':
+				buf.WriteString(`\r`)
+			case '\t':
+				buf.WriteString(`\t`)
+			default:
+				buf.WriteString(`\u00`)
+				buf.WriteByte(hex[b>>4])
+				buf.WriteByte(hex[b&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+		c, size := utf8.DecodeRuneInString(s[i:])
+		if c == utf8.RuneError && size == 1 {
+			buf.WriteString(s[start:i])
+			buf.WriteString(`\ufffd`)
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	buf.WriteString(s[start:])
+	buf.WriteByte('"')
+}
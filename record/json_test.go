@@ -0,0 +1,172 @@
+package record
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mengdu/mo"
+)
+
+func encodeJSON(t *testing.T, e JSONEncoder, entry mo.Entry) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := e.Encode(&buf, entry); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestJSONEncoder_Floats(t *testing.T) {
+	got := encodeJSON(t, JSONEncoder{}, mo.Entry{Level: mo.LevelInfo, Message: "msg", Fields: []mo.Field{
+		mo.Float64("nan", math.NaN()),
+		mo.Float64("inf", math.Inf(1)),
+		mo.Float32("ninf", float32(math.Inf(-1))),
+		mo.Float64("f", 0.25),
+	}})
+	want := `{"level":"info","msg":"msg","nan":"NaN","inf":"+Inf","ninf":"-Inf","f":0.25}` + "\n"
+	if got != want {
+		t.Errorf("got %s want %s", got, want)
+	}
+}
+
+func TestJSONEncoder(t *testing.T) {
+	ts := time.Date(2024, 10, 18, 9, 30, 0, 0, time.UTC)
+	err := fmt.Errorf("load: %w", codeError{errors.New("timeout")})
+	got := encodeJSON(t, JSONEncoder{}, mo.Entry{Level: mo.LevelWarn, Message: "say \"hi\"\n", Fields: []mo.Field{
+		mo.Time(KeyTimestamp, ts),
+		mo.String("s", "a\tb\xff"),
+		mo.Int("i", -1),
+		mo.Uint64("u", math.MaxUint64),
+		mo.Bool("b", true),
+		mo.Duration("d", time.Second),
+		mo.Any("m", map[string]int{"x": 1}),
+		mo.Err(err),
+	}})
+	want := `{"level":"warn","msg":"say \"hi\"\n","ts":"2024-10-18T09:30:00Z","s":"a\tb\ufffd","i":-1,` +
+		`"u":18446744073709551615,"b":true,"d":"1s","m":{"x":1},` +
+		`"error":"load: query: timeout","error.type":"*fmt.wrapError","error.causes":[` +
+		`{"message":"query: timeout","type":"record.codeError"},{"message":"timeout","type":"*errors.errorString"}],` +
+		`"error.code":42}` + "\n"
+	if got != want {
+		t.Errorf("got  %swant %s", got, want)
+	}
+	if !json.Valid([]byte(got)) {
+		t.Errorf("invalid JSON %s", got)
+	}
+}
+
+func TestJSONEncoder_ReservedKeys(t *testing.T) {
+	fields := []mo.Field{mo.String(KeyLevel, "custom"), mo.NamedErr(KeyMessage, errors.New("boom"))}
+	got := encodeJSON(t, JSONEncoder{}, mo.Entry{Level: mo.LevelInfo, Message: "msg", Fields: fields})
+	want := `{"level":"info","msg":"msg","fields.level":"custom","fields.msg":"boom","fields.msg.type":"*errors.errorString"}` + "\n"
+	if got != want {
+		t.Errorf("got  %swant %s", got, want)
+	}
+
+	// The encoding/json path renames them too.
+	var buf bytes.Buffer
+	l := &JSON{Encoder: json.NewEncoder(&buf)}
+	l.Log(context.Background(), mo.LevelInfo, "msg", fields)
+	if got := buf.String(); got != `{"fields.level":"custom","fields.msg":"boom","fields.msg.type":"*errors.errorString","level":"info","msg":"msg"}`+"\n" {
+		t.Errorf("got %s", got)
+	}
+}
+
+func TestJSONEncoder_Stacktrace(t *testing.T) {
+	st := mo.Stack()
+	entry := mo.Entry{Level: mo.LevelError, Message: "failed", Fields: []mo.Field{st}}
+
+	var line struct {
+		Stacktrace string `json:"stacktrace"`
+	}
+	if err := json.Unmarshal([]byte(encodeJSON(t, JSONEncoder{}, entry)), &line); err != nil {
+		t.Fatal(err)
+	}
+	if line.Stacktrace != st.Stacktrace().String() {
+		t.Errorf("stacktrace = %q", line.Stacktrace)
+	}
+
+	var frames struct {
+		Stacktrace []struct {
+			Function string `json:"function"`
+			File     string `json:"file"`
+			Line     int    `json:"line"`
+		} `json:"stacktrace"`
+	}
+	if err := json.Unmarshal([]byte(encodeJSON(t, JSONEncoder{StackFrames: true}, entry)), &frames); err != nil {
+		t.Fatal(err)
+	}
+	want := st.Stacktrace().Frames()
+	if len(frames.Stacktrace) != len(want) {
+		t.Fatalf("got %d frames, want %d", len(frames.Stacktrace), len(want))
+	}
+	for i, f := range frames.Stacktrace {
+		if f.Function != want[i].Function || f.File != want[i].File || f.Line != want[i].Line {
+			t.Errorf("frame %d = %+v, want %s %s:%d", i, f, want[i].Function, want[i].File, want[i].Line)
+		}
+	}
+
+	// The encoding/json path writes the stack trace as a string too.
+	var buf bytes.Buffer
+	l := &JSON{Encoder: json.NewEncoder(&buf)}
+	l.Log(context.Background(), mo.LevelError, "failed", entry.Fields)
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil || !strings.HasPrefix(line.Stacktrace, "github.com/mengdu/mo/record.TestJSONEncoder_Stacktrace\n") {
+		t.Errorf("stacktrace = %q, %v", line.Stacktrace, err)
+	}
+}
+
+func TestJSON_StackFrames(t *testing.T) {
+	st := mo.Stack()
+	want := st.Stacktrace().Frames()
+
+	var out, enc bytes.Buffer
+	for _, l := range []*JSON{
+		{Output: &out, StackFrames: true},
+		{Encoder: json.NewEncoder(&enc), StackFrames: true},
+	} {
+		l.Log(context.Background(), mo.LevelError, "failed", []mo.Field{st})
+	}
+	for _, b := range [][]byte{out.Bytes(), enc.Bytes()} {
+		var line struct {
+			Stacktrace []struct {
+				Function string `json:"function"`
+				File     string `json:"file"`
+				Line     int    `json:"line"`
+			} `json:"stacktrace"`
+		}
+		if err := json.Unmarshal(b, &line); err != nil {
+			t.Fatalf("%s: %v", b, err)
+		}
+		if len(line.Stacktrace) != len(want) {
+			t.Fatalf("got %d frames, want %d", len(line.Stacktrace), len(want))
+		}
+		for i, f := range line.Stacktrace {
+			if f.Function != want[i].Function || f.File != want[i].File || f.Line != want[i].Line {
+				t.Errorf("frame %d = %+v, want %s %s:%d", i, f, want[i].Function, want[i].File, want[i].Line)
+			}
+		}
+	}
+}
+
+func TestJSON_Encoder(t *testing.T) {
+	var buf bytes.Buffer
+	l := &JSON{Encoder: json.NewEncoder(&buf)}
+	l.Log(context.Background(), mo.LevelInfo, "msg", []mo.Field{
+		mo.Float64("nan", math.NaN()),
+		mo.Float64("inf", math.Inf(1)),
+		mo.Float32("ninf", float32(math.Inf(-1))),
+		mo.Float64("f", 0.25),
+		mo.Duration("d", 1500*time.Millisecond),
+	})
+	want := `{"d":"1.5s","f":0.25,"inf":"+Inf","level":"info","msg":"msg","nan":"NaN","ninf":"-Inf"}` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got  %swant %s", got, want)
+	}
+}
@@ -0,0 +1,61 @@
+package record
+
+import (
+	"context"
+
+	"github.com/mengdu/mo"
+)
+
+// MinLevel returns a recorder that records the log messages at level and above to r
+// and discards the others. Flushing or closing it flushes or closes r.
+//
+// The logger's own level is checked first, so it must be at or below level for
+// the messages to reach the recorder.
+func MinLevel(level mo.Level, r mo.Recorder) mo.Recorder {
+	return &minLevel{level: level, r: r}
+}
+
+type minLevel struct {
+	level mo.Level
+	r     mo.Recorder
+}
+
+// Log implements the Recorder interface.
+func (m *minLevel) Log(ctx context.Context, level mo.Level, msg string, kv []mo.Field) {
+	if level >= m.level {
+		m.r.Log(ctx, level, msg, kv)
+	}
+}
+
+// Flush flushes the wrapped recorder if it implements mo.Flusher.
+func (m *minLevel) Flush() error {
+	return mo.FlushRecorder(m.r)
+}
+
+// Close closes the wrapped recorder, see mo.CloseRecorder.
+func (m *minLevel) Close() error {
+	return mo.CloseRecorder(m.r)
+}
+
+// Leveled is a recorder with the minimum level of the log messages it records, see CombineLevels.
+type Leveled struct {
+	Level    mo.Level
+	Recorder mo.Recorder
+}
+
+// CombineLevels returns a recorder that records log messages to each of the given
+// recorders whose level is at or below the level of the message, such as:
+//
+//	record.CombineLevels(
+//		record.Leveled{Level: mo.LevelDebug, Recorder: file},
+//		record.Leveled{Level: mo.LevelWarn, Recorder: console},
+//	)
+//
+// Flushing or closing it flushes or closes each of them, see mo.Combine.
+func CombineLevels(a ...Leveled) mo.Recorder {
+	rs := make([]mo.Recorder, len(a))
+	for i, v := range a {
+		rs[i] = MinLevel(v.Level, v.Recorder)
+	}
+	return mo.Combine(rs...)
+}
@@ -0,0 +1,22 @@
+package record
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mengdu/mo"
+)
+
+func TestCombineLevels(t *testing.T) {
+	file, console := countRecorder{}, countRecorder{}
+	r := CombineLevels(
+		Leveled{Level: mo.LevelDebug, Recorder: file},
+		Leveled{Level: mo.LevelWarn, Recorder: console},
+	)
+	for _, level := range []mo.Level{mo.LevelDebug, mo.LevelInfo, mo.LevelWarn, mo.LevelError} {
+		r.Log(context.Background(), level, "msg", nil)
+	}
+	if file["msg"] != 4 || console["msg"] != 2 {
+		t.Fatalf("file got %d messages, console got %d", file["msg"], console["msg"])
+	}
+}
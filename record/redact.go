@@ -0,0 +1,325 @@
+package record
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mengdu/mo"
+)
+
+// Built-in value patterns of Redactor.
+var (
+	// CreditCardPattern matches credit card numbers of 13 to 19 digits, optionally grouped by spaces or dashes.
+	// Redactor masks only the matches passing the Luhn checksum, so that most other long
+	// numbers, such as timestamps in nanoseconds, are kept.
+	CreditCardPattern = regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`)
+	// EmailPattern matches email addresses.
+	EmailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`)
+	// JWTPattern matches JSON Web Tokens.
+	JWTPattern = regexp.MustCompile(`\beyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+)
+
+// DefaultRedactKeys are the key patterns used by Redactor when Keys is nil.
+var DefaultRedactKeys = []string{
+	"*password*", "passwd", "pwd", "*secret*", "*token*", "authorization", "cookie", "set-cookie", "*api_key*", "*apikey*",
+}
+
+// DefaultRedactValues are the value patterns used by Redactor when Values is nil.
+var DefaultRedactValues = []*regexp.Regexp{CreditCardPattern, EmailPattern, JWTPattern}
+
+// MaskAll replaces s with "***".
+func MaskAll(s string) string {
+	return "***"
+}
+
+// MaskPartial replaces s with "***" followed by its last 4 characters, such as "***4242".
+// Strings of 8 characters or less are replaced with "***".
+func MaskPartial(s string) string {
+	n := utf8.RuneCountInString(s)
+	if n <= 8 {
+		return "***"
+	}
+	i := len(s)
+	for k := 0; k < 4; k++ {
+		_, size := utf8.DecodeLastRuneInString(s[:i])
+		i -= size
+	}
+	return "***" + s[i:]
+}
+
+// Redactor is a recorder that masks secrets and personal data in log messages before
+// they are written to Recorder:
+//
+//   - the values of fields whose key, or the last dotted segment of it, matches one of Keys;
+//   - the parts of messages, string and integer fields and error messages matching one of Values;
+//   - the messages, causes and fields of errors, see mo.DescribeError, which are passed
+//     to Recorder as errors describing the redacted details;
+//   - both of the above inside maps, slices and structs, which are always passed to Recorder
+//     in their redacted JSON form, as map[string]interface{}, []interface{} and json.Number
+//     values, so that unexported fields printed by the text recorders are never written.
+//
+// Key patterns are matched case-insensitively and may contain '*' wildcards, such as "*token*".
+//
+// The fields of a Redactor must not be changed after the first log message.
+type Redactor struct {
+	Recorder mo.Recorder
+	Keys     []string              // Key patterns, DefaultRedactKeys if nil
+	Values   []*regexp.Regexp      // Value patterns, DefaultRedactValues if nil
+	Mask     func(s string) string // Replaces the redacted values, MaskAll if nil
+}
+
+// Log implements the Recorder interface.
+func (r *Redactor) Log(ctx context.Context, level mo.Level, msg string, kv []mo.Field) {
+	msg = r.redactString(msg)
+	var out []mo.Field // Copy of kv, made on the first change
+	for i, v := range kv {
+		f, changed := r.redactField(v)
+		if !changed {
+			continue
+		}
+		if out == nil {
+			out = append(make([]mo.Field, 0, len(kv)), kv...)
+		}
+		out[i] = f
+	}
+	if out == nil {
+		out = kv
+	}
+	r.Recorder.Log(ctx, level, msg, out)
+}
+
+// Flush flushes Recorder if it implements mo.Flusher.
+func (r *Redactor) Flush() error {
+	return mo.FlushRecorder(r.Recorder)
+}
+
+// Close closes Recorder, see mo.CloseRecorder.
+func (r *Redactor) Close() error {
+	return mo.CloseRecorder(r.Recorder)
+}
+
+func (r *Redactor) mask(s string) string {
+	if r.Mask != nil {
+		return r.Mask(s)
+	}
+	return MaskAll(s)
+}
+
+// redactField returns the redacted field and whether it differs from v.
+func (r *Redactor) redactField(v mo.Field) (mo.Field, bool) {
+	if r.matchKey(v.Key()) {
+		return mo.String(v.Key(), r.mask(v.Text())), true
+	}
+
+	switch v.Type() {
+	case mo.StringType:
+		if s := r.redactString(v.Str()); s != v.Str() {
+			return mo.String(v.Key(), s), true
+		}
+	case mo.Int64Type, mo.Uint64Type:
+		// Card numbers logged as integers.
+		s := v.Text()
+		if redacted := r.redactString(s); redacted != s {
+			return mo.String(v.Key(), redacted), true
+		}
+	case mo.ErrorType:
+		if d, changed := r.redactError(mo.DescribeError(v.Err())); changed {
+			return mo.NamedErr(v.Key(), redactedError{d}), true
+		}
+	case mo.AnyType:
+		return r.redactAny(v)
+	}
+	return v, false
+}
+
+// redactedError is a redacted error, described by the redacted details of the original error.
+// It doesn't unwrap to the original error so that its secrets can't be reached.
+type redactedError struct {
+	d mo.ErrorDetails
+}
+
+func (e redactedError) Error() string                  { return e.d.Message }
+func (e redactedError) DescribeError() mo.ErrorDetails { return e.d }
+
+// redactError returns the redacted message, causes and fields of d and whether they changed.
+func (r *Redactor) redactError(d mo.ErrorDetails) (mo.ErrorDetails, bool) {
+	out := mo.ErrorDetails{Message: r.redactString(d.Message), Type: d.Type}
+	changed := out.Message != d.Message
+	for _, c := range d.Causes {
+		msg := r.redactString(c.Message)
+		changed = changed || msg != c.Message
+		out.Causes = append(out.Causes, mo.ErrorCause{Message: msg, Type: c.Type})
+	}
+	for _, v := range d.Fields {
+		f, ok := r.redactField(v)
+		changed = changed || ok
+		out.Fields = append(out.Fields, f)
+	}
+	return out, changed
+}
+
+// redactAny redacts the value of an AnyType field. Composite values are replaced with their
+// redacted JSON form, even if unchanged, as their fmt form may show unexported fields.
+func (r *Redactor) redactAny(v mo.Field) (mo.Field, bool) {
+	value := v.Any()
+	if value == nil {
+		return v, false
+	}
+
+	switch reflect.Indirect(reflect.ValueOf(value)).Kind() {
+	case reflect.Map, reflect.Struct, reflect.Slice, reflect.Array:
+		b, err := json.Marshal(value)
+		if err != nil {
+			break
+		}
+		// Decode numbers as json.Number to match their digits with the value patterns.
+		var generic interface{}
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.UseNumber()
+		if err := dec.Decode(&generic); err != nil {
+			break
+		}
+		redacted, _ := r.redactValue(generic)
+		return mo.Any(v.Key(), redacted), true
+	}
+
+	s := fmt.Sprint(value)
+	if redacted := r.redactString(s); redacted != s {
+		return mo.String(v.Key(), redacted), true
+	}
+	return v, false
+}
+
+// redactValue redacts a value decoded from JSON in place and returns whether it changed.
+func (r *Redactor) redactValue(v interface{}) (interface{}, bool) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		changed := false
+		for k, e := range v {
+			if r.matchKey(k) {
+				if s, ok := e.(string); ok {
+					v[k] = r.mask(s)
+				} else {
+					v[k] = r.mask(fmt.Sprint(e))
+				}
+				changed = true
+				continue
+			}
+			if redacted, ok := r.redactValue(e); ok {
+				v[k] = redacted
+				changed = true
+			}
+		}
+		return v, changed
+	case []interface{}:
+		changed := false
+		for i, e := range v {
+			if redacted, ok := r.redactValue(e); ok {
+				v[i] = redacted
+				changed = true
+			}
+		}
+		return v, changed
+	case string:
+		s := r.redactString(v)
+		return s, s != v
+	case json.Number:
+		if s := r.redactString(string(v)); s != string(v) {
+			return s, true
+		}
+		return v, false
+	default:
+		return v, false
+	}
+}
+
+// redactString masks the parts of s matching the value patterns.
+func (r *Redactor) redactString(s string) string {
+	values := r.Values
+	if values == nil {
+		values = DefaultRedactValues
+	}
+	for _, re := range values {
+		if re == CreditCardPattern {
+			s = re.ReplaceAllStringFunc(s, r.maskCreditCard)
+			continue
+		}
+		s = re.ReplaceAllStringFunc(s, r.mask)
+	}
+	return s
+}
+
+// maskCreditCard masks s if its digits pass the Luhn checksum.
+func (r *Redactor) maskCreditCard(s string) string {
+	if !luhn(s) {
+		return s
+	}
+	return r.mask(s)
+}
+
+// luhn reports whether the digits of s, ignoring other characters, pass the Luhn checksum.
+func luhn(s string) bool {
+	sum, double := 0, false
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// matchKey reports whether key, or its last dotted segment, matches one of the key patterns.
+func (r *Redactor) matchKey(key string) bool {
+	keys := r.Keys
+	if keys == nil {
+		keys = DefaultRedactKeys
+	}
+	key = strings.ToLower(key)
+	last := key
+	if i := strings.LastIndexByte(key, '.'); i != -1 {
+		last = key[i+1:]
+	}
+	for _, pattern := range keys {
+		pattern = strings.ToLower(pattern)
+		if matchWildcard(pattern, key) || matchWildcard(pattern, last) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchWildcard reports whether s matches pattern, in which '*' matches any sequence of characters.
+func matchWildcard(pattern, s string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == s
+	}
+	if !strings.HasPrefix(s, parts[0]) {
+		return false
+	}
+	s = s[len(parts[0]):]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(s, part)
+		if i == -1 {
+			return false
+		}
+		s = s[i+len(part):]
+	}
+	return strings.HasSuffix(s, parts[len(parts)-1])
+}
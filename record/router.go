@@ -0,0 +1,174 @@
+package record
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/mengdu/mo"
+)
+
+// Matcher reports whether a log message matches a condition of a Route.
+type Matcher func(e mo.Entry) bool
+
+// MatchLevel matches the log messages at level and above.
+func MatchLevel(level mo.Level) Matcher {
+	return func(e mo.Entry) bool {
+		return e.Level >= level
+	}
+}
+
+// MatchMessage matches the log messages equal to msg.
+func MatchMessage(msg string) Matcher {
+	return func(e mo.Entry) bool {
+		return e.Message == msg
+	}
+}
+
+// MatchMessagePrefix matches the log messages starting with prefix.
+func MatchMessagePrefix(prefix string) Matcher {
+	return func(e mo.Entry) bool {
+		return strings.HasPrefix(e.Message, prefix)
+	}
+}
+
+// MatchMessageRegexp matches the log messages matching re.
+func MatchMessageRegexp(re *regexp.Regexp) Matcher {
+	return func(e mo.Entry) bool {
+		return re.MatchString(e.Message)
+	}
+}
+
+// MatchFieldPresent matches the log messages with a field with the given key.
+func MatchFieldPresent(key string) Matcher {
+	return func(e mo.Entry) bool {
+		_, ok := lookupField(e.Fields, key)
+		return ok
+	}
+}
+
+// MatchField matches the log messages with a field with the given key whose
+// textual value, as written by Console, equals value.
+func MatchField(key, value string) Matcher {
+	return func(e mo.Entry) bool {
+		v, ok := lookupField(e.Fields, key)
+		return ok && v.Text() == value
+	}
+}
+
+// MatchFieldPrefix matches the log messages with a field with the given key whose
+// textual value starts with prefix.
+func MatchFieldPrefix(key, prefix string) Matcher {
+	return func(e mo.Entry) bool {
+		v, ok := lookupField(e.Fields, key)
+		return ok && strings.HasPrefix(v.Text(), prefix)
+	}
+}
+
+// MatchFieldRegexp matches the log messages with a field with the given key whose
+// textual value matches re.
+func MatchFieldRegexp(key string, re *regexp.Regexp) Matcher {
+	return func(e mo.Entry) bool {
+		v, ok := lookupField(e.Fields, key)
+		return ok && re.MatchString(v.Text())
+	}
+}
+
+// lookupField returns the last field with the given key, which takes precedence
+// over the base and context fields with the same key.
+func lookupField(kv []mo.Field, key string) (mo.Field, bool) {
+	for i := len(kv) - 1; i >= 0; i-- {
+		if kv[i].Key() == key {
+			return kv[i], true
+		}
+	}
+	return mo.Field{}, false
+}
+
+// Route sends the log messages matching all of Match to Recorder.
+// A Route without matchers matches all log messages.
+type Route struct {
+	Match    []Matcher
+	Recorder mo.Recorder
+}
+
+func (r Route) matches(e mo.Entry) bool {
+	for _, m := range r.Match {
+		if !m(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// Router is a recorder that dispatches log messages to recorders by rules on their
+// level, message and fields. Routes are evaluated in order; a log message is recorded
+// to the first matching route, or to all matching routes if All is set, and to Default
+// if no route matches:
+//
+//	log.SetRecorder(&record.Router{
+//		Routes: []record.Route{
+//			{Match: []record.Matcher{record.MatchField("kind", "audit")}, Recorder: audit},
+//			{Match: []record.Matcher{record.MatchField("tenant", "acme")}, Recorder: acme},
+//		},
+//		Default: console,
+//	})
+//
+// The fields of a Router must not be changed after the first log message.
+type Router struct {
+	Routes  []Route
+	Default mo.Recorder
+	All     bool
+}
+
+// Log implements the Recorder interface.
+func (r *Router) Log(ctx context.Context, level mo.Level, msg string, kv []mo.Field) {
+	e := mo.Entry{Level: level, Message: msg, Fields: kv}
+	matched := false
+	for _, route := range r.Routes {
+		if !route.matches(e) {
+			continue
+		}
+		route.Recorder.Log(ctx, level, msg, kv)
+		matched = true
+		if !r.All {
+			break
+		}
+	}
+	if !matched && r.Default != nil {
+		r.Default.Log(ctx, level, msg, kv)
+	}
+}
+
+// recorders returns the recorders of the routes and Default, each once.
+func (r *Router) recorders() []mo.Recorder {
+	rs := make([]mo.Recorder, 0, len(r.Routes)+1)
+	add := func(rec mo.Recorder) {
+		if rec == nil {
+			return
+		}
+		t := reflect.TypeOf(rec)
+		for _, v := range rs {
+			if t.Comparable() && reflect.TypeOf(v) == t && v == rec {
+				return
+			}
+		}
+		rs = append(rs, rec)
+	}
+	for _, route := range r.Routes {
+		add(route.Recorder)
+	}
+	add(r.Default)
+	return rs
+}
+
+// Flush flushes the recorders of all routes and returns the first error.
+func (r *Router) Flush() error {
+	return mo.FlushRecorder(mo.Combine(r.recorders()...))
+}
+
+// Close closes the recorders of all routes and returns the first error.
+func (r *Router) Close() error {
+	return mo.CloseRecorder(mo.Combine(r.recorders()...))
+}
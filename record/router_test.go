@@ -0,0 +1,59 @@
+package record
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/mengdu/mo"
+)
+
+func TestRouter(t *testing.T) {
+	audit, tenants, errs, def := countRecorder{}, countRecorder{}, countRecorder{}, countRecorder{}
+	r := &Router{
+		Routes: []Route{
+			{Match: []Matcher{MatchField("kind", "audit"), MatchMessagePrefix("user.")}, Recorder: audit},
+			{Match: []Matcher{MatchFieldRegexp("tenant", regexp.MustCompile(`^t\d+$`))}, Recorder: tenants},
+			{Match: []Matcher{MatchLevel(mo.LevelError), MatchFieldPresent("error")}, Recorder: errs},
+		},
+		Default: def,
+	}
+
+	entries := []struct {
+		level mo.Level
+		msg   string
+		kv    []mo.Field
+	}{
+		{mo.LevelInfo, "user.login", []mo.Field{mo.String("kind", "audit"), mo.String("tenant", "t1")}},
+		{mo.LevelInfo, "login", []mo.Field{mo.String("kind", "audit")}},
+		{mo.LevelError, "query", []mo.Field{mo.String("tenant", "t2"), mo.Err(context.Canceled)}},
+		{mo.LevelError, "fail", []mo.Field{mo.Err(context.Canceled)}},
+		{mo.LevelWarn, "slow", []mo.Field{mo.String("tenant", "acme")}},
+	}
+	for _, all := range []bool{false, true} {
+		r.All = all
+		for _, e := range entries {
+			r.Log(context.Background(), e.level, e.msg, e.kv)
+		}
+	}
+
+	want := []struct {
+		name string
+		got  countRecorder
+		n    int
+	}{
+		{"audit", audit, 2},
+		{"tenants", tenants, 1 + 2},
+		{"errs", errs, 1 + 2},
+		{"default", def, 2 * 2},
+	}
+	for _, w := range want {
+		n := 0
+		for _, c := range w.got {
+			n += c
+		}
+		if n != w.n {
+			t.Errorf("%s got %d messages, want %d: %v", w.name, n, w.n, w.got)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package record
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mengdu/mo"
+)
+
+// Sampler is a recorder that caps the volume of repetitive log messages written to Recorder.
+// For each level and message, it passes the First entries logged in every Tick interval,
+// then every Thereafter-th entry, and drops the others:
+//
+//	log.SetRecorder(&record.Sampler{
+//		Recorder:   &record.Console{Stdout: os.Stdout, Stderr: os.Stderr},
+//		Tick:       time.Second,
+//		First:      100,
+//		Thereafter: 100,
+//	})
+//
+// The fields of a Sampler must not be changed after the first log message.
+type Sampler struct {
+	passed  uint64 // Accessed atomically, first for 64-bit alignment
+	dropped uint64 // Accessed atomically
+
+	Recorder   mo.Recorder
+	Tick       time.Duration // Sampling interval, defaults to one second
+	First      int           // Number of entries passed in each interval before sampling
+	Thereafter int           // Pass every Thereafter-th entry after First, 0 drops them all
+
+	mu     sync.Mutex
+	counts map[sampleKey]*sampleCount
+	swept  time.Time        // Time of the last removal of expired counts
+	now    func() time.Time // Overridden by tests
+}
+
+type sampleKey struct {
+	level mo.Level
+	msg   string
+}
+
+type sampleCount struct {
+	start time.Time // Start of the current interval
+	n     int       // Number of entries logged in the current interval
+}
+
+// Log implements the Recorder interface.
+func (s *Sampler) Log(ctx context.Context, level mo.Level, msg string, kv []mo.Field) {
+	if !s.sample(level, msg) {
+		atomic.AddUint64(&s.dropped, 1)
+		return
+	}
+	atomic.AddUint64(&s.passed, 1)
+	s.Recorder.Log(ctx, level, msg, kv)
+}
+
+// sample reports whether the entry with the given level and message is passed.
+func (s *Sampler) sample(level mo.Level, msg string) bool {
+	tick := s.Tick
+	if tick <= 0 {
+		tick = time.Second
+	}
+	now := time.Now()
+	if s.now != nil {
+		now = s.now()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.counts == nil {
+		s.counts = make(map[sampleKey]*sampleCount)
+	}
+	// Remove the counts of messages not logged in the last interval,
+	// so that the map does not grow with every distinct message.
+	if now.Sub(s.swept) >= tick {
+		for k, c := range s.counts {
+			if now.Sub(c.start) >= tick {
+				delete(s.counts, k)
+			}
+		}
+		s.swept = now
+	}
+
+	key := sampleKey{level, msg}
+	c, ok := s.counts[key]
+	if !ok {
+		c = &sampleCount{start: now}
+		s.counts[key] = c
+	} else if now.Sub(c.start) >= tick {
+		c.start, c.n = now, 0
+	}
+	c.n++
+
+	if c.n <= s.First {
+		return true
+	}
+	return s.Thereafter > 0 && (c.n-s.First)%s.Thereafter == 0
+}
+
+// Passed returns the number of entries passed to Recorder.
+func (s *Sampler) Passed() uint64 {
+	return atomic.LoadUint64(&s.passed)
+}
+
+// Dropped returns the number of entries dropped by sampling.
+func (s *Sampler) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+// Flush flushes Recorder if it implements mo.Flusher.
+func (s *Sampler) Flush() error {
+	return mo.FlushRecorder(s.Recorder)
+}
+
+// Close closes Recorder, see mo.CloseRecorder.
+func (s *Sampler) Close() error {
+	return mo.CloseRecorder(s.Recorder)
+}
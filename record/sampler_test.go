@@ -0,0 +1,52 @@
+package record
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mengdu/mo"
+)
+
+// countRecorder counts the log messages by message.
+type countRecorder map[string]int
+
+func (r countRecorder) Log(ctx context.Context, level mo.Level, msg string, kv []mo.Field) {
+	r[msg]++
+}
+
+func TestSampler(t *testing.T) {
+	now := time.Unix(0, 0)
+	r := countRecorder{}
+	s := &Sampler{
+		Recorder:   r,
+		Tick:       time.Second,
+		First:      3,
+		Thereafter: 10,
+		now:        func() time.Time { return now },
+	}
+
+	for i := 0; i < 100; i++ {
+		s.Log(context.Background(), mo.LevelInfo, "cache miss", nil)
+		s.Log(context.Background(), mo.LevelWarn, "cache miss", nil)
+	}
+	s.Log(context.Background(), mo.LevelInfo, "other", nil)
+	// 3 first entries, then entries 13, 23, ..., 93 for each level.
+	if r["cache miss"] != 2*(3+9) || r["other"] != 1 {
+		t.Fatalf("passed %v", r)
+	}
+	if s.Passed() != 25 || s.Dropped() != 176 {
+		t.Fatalf("passed %d, dropped %d", s.Passed(), s.Dropped())
+	}
+
+	now = now.Add(time.Second)
+	for i := 0; i < 5; i++ {
+		s.Log(context.Background(), mo.LevelInfo, "cache miss", nil)
+	}
+	if r["cache miss"] != 2*(3+9)+3 {
+		t.Fatalf("passed %v after tick", r)
+	}
+	if len(s.counts) != 1 {
+		t.Fatalf("expired counts not removed: %d left", len(s.counts))
+	}
+}
@@ -0,0 +1,84 @@
+package record
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/mengdu/mo"
+)
+
+// Encoder encodes a log entry into bytes.
+type Encoder interface {
+	// Encode appends the encoded entry, including any line terminator, to buf.
+	Encode(buf *bytes.Buffer, e mo.Entry) error
+}
+
+// Sink is the destination of encoded log entries. A Sink is flushed with the Writer
+// when it implements mo.Flusher or Sync() error, and closed with it when it implements io.Closer.
+type Sink interface {
+	io.Writer
+}
+
+// Ensure Writer implements the Recorder, Flusher and Closer interfaces.
+var (
+	_ mo.Recorder = (*Writer)(nil)
+	_ mo.Flusher  = (*Writer)(nil)
+	_ mo.Closer   = (*Writer)(nil)
+)
+
+// Writer is a Recorder that encodes log entries with Encoder and writes them to Sink.
+// Writes are serialized, so Sink doesn't need to be safe for concurrent use.
+type Writer struct {
+	Encoder Encoder
+	Sink    Sink
+	// ErrSink, when set, receives the entries at mo.LevelError and above instead of Sink.
+	ErrSink Sink
+	mu      sync.Mutex
+}
+
+var bufPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+// Log implements the Recorder interface.
+func (w *Writer) Log(ctx context.Context, level mo.Level, msg string, kv []mo.Field) {
+	buf := bufPool.Get().(*bytes.Buffer)
+	defer bufPool.Put(buf)
+	defer buf.Reset()
+
+	if err := w.Encoder.Encode(buf, mo.Entry{Level: level, Message: msg, Fields: kv}); err != nil {
+		fmt.Fprintf(os.Stderr, "encode failed: %v\n", err)
+		return
+	}
+
+	out := w.Sink
+	if w.ErrSink != nil && level >= mo.LevelError {
+		out = w.ErrSink
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, err := out.Write(buf.Bytes()); err != nil {
+		fmt.Fprintf(os.Stderr, "write failed: %v\n", err)
+	}
+}
+
+// Flush flushes the sinks.
+func (w *Writer) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return flushWriters(w.Sink, w.ErrSink)
+}
+
+// Close flushes and closes the sinks, except os.Stdout and os.Stderr.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return closeWriters(w.Sink, w.ErrSink)
+}
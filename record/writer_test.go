@@ -0,0 +1,81 @@
+package record
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mengdu/mo"
+)
+
+// sinkBuffer is a Sink recording whether it was flushed and closed.
+type sinkBuffer struct {
+	bytes.Buffer
+	flushed, closed int
+	err             error
+}
+
+func (b *sinkBuffer) Flush() error {
+	b.flushed++
+	return b.err
+}
+
+func (b *sinkBuffer) Close() error {
+	b.closed++
+	return nil
+}
+
+// msgEncoder encodes the level and message of entries.
+type msgEncoder struct{}
+
+func (msgEncoder) Encode(buf *bytes.Buffer, e mo.Entry) error {
+	buf.WriteString(e.Level.Abbr() + " " + e.Message + "\n")
+	return nil
+}
+
+func TestWriter(t *testing.T) {
+	out := &sinkBuffer{}
+	w := &Writer{Encoder: msgEncoder{}, Sink: out}
+	ctx := context.Background()
+	w.Log(ctx, mo.LevelInfo, "a", nil)
+	w.Log(ctx, mo.LevelError, "b", nil)
+	if got := out.String(); got != "INF a\nERR b\n" {
+		t.Errorf("got %q", got)
+	}
+
+	if err := w.Flush(); err != nil || out.flushed != 1 {
+		t.Errorf("Flush() = %v, flushed %d times", err, out.flushed)
+	}
+	if err := w.Close(); err != nil || out.flushed != 2 || out.closed != 1 {
+		t.Errorf("Close() = %v, flushed %d and closed %d times", err, out.flushed, out.closed)
+	}
+	out.err = errors.New("flush failed")
+	if err := w.Flush(); err != out.err {
+		t.Errorf("Flush() = %v, want %v", err, out.err)
+	}
+}
+
+func TestWriter_ErrSink(t *testing.T) {
+	out, errOut := &sinkBuffer{}, &sinkBuffer{}
+	w := &Writer{Encoder: msgEncoder{}, Sink: out, ErrSink: errOut}
+	for _, level := range []mo.Level{mo.LevelDebug, mo.LevelInfo, mo.LevelWarn, mo.LevelError, mo.LevelPanic, mo.LevelFatal} {
+		w.Log(context.Background(), level, "msg", nil)
+	}
+	if got := out.String(); got != "DBG msg\nINF msg\nWRN msg\n" {
+		t.Errorf("Sink got %q", got)
+	}
+	if got := errOut.String(); got != "ERR msg\nPNC msg\nFTL msg\n" {
+		t.Errorf("ErrSink got %q", got)
+	}
+
+	if err := w.Close(); err != nil || out.closed != 1 || errOut.closed != 1 {
+		t.Errorf("Close() = %v, closed %d and %d times", err, out.closed, errOut.closed)
+	}
+
+	// A sink used for both is flushed and closed once.
+	w = &Writer{Encoder: msgEncoder{}, Sink: out, ErrSink: out}
+	if err := w.Close(); err != nil || out.closed != 2 {
+		t.Errorf("Close() = %v, closed %d times", err, out.closed)
+	}
+}
@@ -15,6 +15,41 @@ type Recorder interface {
 	Log(ctx context.Context, level Level, msg string, kv []Field)
 }
 
+// Entry is a log message with its level and key-value pairs.
+type Entry struct {
+	Level   Level
+	Message string
+	Fields  []Field
+}
+
+// Flusher is implemented by recorders that buffer log messages.
+// Flush writes out all buffered messages.
+type Flusher interface {
+	Flush() error
+}
+
+// Closer is implemented by recorders that hold resources such as files.
+// Close flushes buffered messages and releases the resources.
+type Closer interface {
+	Close() error
+}
+
+// FlushRecorder flushes r if it implements Flusher.
+func FlushRecorder(r Recorder) error {
+	if f, ok := r.(Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+// CloseRecorder closes r if it implements Closer, otherwise it flushes r if it implements Flusher.
+func CloseRecorder(r Recorder) error {
+	if c, ok := r.(Closer); ok {
+		return c.Close()
+	}
+	return FlushRecorder(r)
+}
+
 type combine struct {
 	Recorders []Recorder
 }
@@ -25,6 +60,30 @@ func (c combine) Log(ctx context.Context, level Level, msg string, kv []Field) {
 	}
 }
 
+// Flush flushes all recorders and returns the first error.
+func (c combine) Flush() error {
+	var first error
+	for _, v := range c.Recorders {
+		if err := FlushRecorder(v); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// Close closes all recorders and returns the first error.
+func (c combine) Close() error {
+	var first error
+	for _, v := range c.Recorders {
+		if err := CloseRecorder(v); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// Combine returns a Recorder that records log messages to all of the given recorders.
+// Flushing or closing it flushes or closes each of them.
 func Combine(a ...Recorder) Recorder {
 	return combine{Recorders: a}
 }
@@ -45,49 +104,7 @@ func (r *stdRecorder) Log(ctx context.Context, level Level, msg string, kv []Fie
 	buf := r.pool.Get().(*bytes.Buffer)
 	defer r.pool.Put(buf)
 	defer buf.Reset()
-	ts := ""
-	caller := ""
-	for _, v := range kv {
-		if v.Key() == "ts" {
-			ts = fmt.Sprint(v.Value())
-		}
-		if v.Key() == "caller" {
-			if file, ok := v.Value().(string); ok {
-				caller = file
-			}
-		}
-	}
-
-	if ts != "" {
-		buf.WriteString("[")
-		buf.WriteString(ts)
-		buf.WriteString("]")
-	}
-
-	buf.WriteString("[")
-	buf.WriteString(level.Abbr())
-	buf.WriteString("] ")
-	buf.WriteString(msg)
-
-	i := 0
-	for _, v := range kv {
-		if v.Key() == "ts" || v.Key() == "caller" {
-			continue
-		}
-
-		if i > 0 {
-			buf.WriteString(", ")
-		} else {
-			buf.WriteString(" ")
-		}
-		fmt.Fprintf(buf, "%s=%v", v.Key(), v.Value())
-		i++
-	}
-	if caller != "" {
-		buf.WriteString(" ")
-		buf.WriteString(caller)
-	}
-	buf.WriteByte('\n')
+	TextFormat{}.Write(buf, Entry{Level: level, Message: msg, Fields: kv})
 
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -102,6 +119,19 @@ func (r *stdRecorder) Log(ctx context.Context, level Level, msg string, kv []Fie
 	}
 }
 
+// Flush flushes the standard output and error writers if they buffer writes.
+// The writers are never closed, so Flush also serves as Close.
+func (r *stdRecorder) Flush() error {
+	for _, w := range []io.Writer{r.stdout, r.stderr} {
+		if f, ok := w.(Flusher); ok {
+			if err := f.Flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // DefaultRecorder is the default Recorder implementation that writes to os.Stdout and os.Stderr.
 var DefaultRecorder = &stdRecorder{
 	stdout: os.Stdout,
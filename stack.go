@@ -0,0 +1,71 @@
+package mo
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// StacktraceKey is the key of the stack trace field added by Logger.SetStacktrace and Stack.
+const StacktraceKey = "stacktrace"
+
+// Stacktrace is the program counters of a captured call stack, innermost first.
+type Stacktrace []uintptr
+
+// Frames returns the frames of the stack trace.
+func (s Stacktrace) Frames() []runtime.Frame {
+	if len(s) == 0 {
+		return nil
+	}
+	frames := make([]runtime.Frame, 0, len(s))
+	it := runtime.CallersFrames(s)
+	for {
+		frame, more := it.Next()
+		frames = append(frames, frame)
+		if !more {
+			break
+		}
+	}
+	return frames
+}
+
+// String formats the stack trace like the stack traces of panics, with the
+// function on one line followed by the indented file and line on the next.
+func (s Stacktrace) String() string {
+	var b strings.Builder
+	for i, frame := range s.Frames() {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(frame.Function)
+		b.WriteString("\n\t")
+		b.WriteString(frame.File)
+		b.WriteByte(':')
+		b.WriteString(strconv.Itoa(frame.Line))
+	}
+	return b.String()
+}
+
+// captureStack returns the stack of the calling goroutine, skipping skip frames
+// above the caller of captureStack.
+func captureStack(skip int) Stacktrace {
+	pcs := make([]uintptr, 64)
+	for {
+		n := runtime.Callers(skip+2, pcs)
+		if n < len(pcs) {
+			return Stacktrace(pcs[:n])
+		}
+		pcs = make([]uintptr, len(pcs)*2)
+	}
+}
+
+// Stack returns a field with the StacktraceKey key holding the stack trace of the caller.
+func Stack() Field {
+	return StackSkip(1)
+}
+
+// StackSkip returns a field with the StacktraceKey key holding the stack trace of the caller,
+// skipping skip additional frames.
+func StackSkip(skip int) Field {
+	return Field{key: StacktraceKey, typ: StackType, iface: captureStack(skip + 1)}
+}
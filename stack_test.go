@@ -0,0 +1,116 @@
+package mo
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// stackOf returns the stack trace field of kv.
+func stackOf(kv []Field) (Stacktrace, bool) {
+	for _, v := range kv {
+		if v.Type() == StackType {
+			return v.Stacktrace(), v.Key() == StacktraceKey
+		}
+	}
+	return nil, false
+}
+
+// topFunction returns the innermost function of the stack trace.
+func topFunction(st Stacktrace) string {
+	frames := st.Frames()
+	if len(frames) == 0 {
+		return ""
+	}
+	return frames[0].Function
+}
+
+func TestLogger_SetStacktrace(t *testing.T) {
+	r := &fieldsRecorder{}
+	logger := NewLogger(r)
+	ctx := context.Background()
+
+	logger.Printw(ctx, LevelFatal, "msg")
+	if _, ok := stackOf(r.kv); ok {
+		t.Error("stack traces should be disabled by default")
+	}
+
+	logger.SetStacktrace(LevelError)
+	logger.Printw(ctx, LevelWarn, "msg")
+	if _, ok := stackOf(r.kv); ok {
+		t.Error("stack trace added below the threshold")
+	}
+	for _, level := range []Level{LevelError, LevelFatal} {
+		logger.Printw(ctx, level, "msg")
+		st, ok := stackOf(r.kv)
+		if !ok {
+			t.Fatalf("no stack trace at level %s", level)
+		}
+		if fn := topFunction(st); fn != "github.com/mengdu/mo.TestLogger_SetStacktrace" {
+			t.Errorf("stack trace starts at %s, want the caller of the logging method", fn)
+		}
+	}
+
+	logger.SetStacktrace(LevelFatal + 1)
+	logger.Printw(ctx, LevelFatal, "msg")
+	if _, ok := stackOf(r.kv); ok {
+		t.Error("a level above LevelFatal should disable stack traces")
+	}
+}
+
+// logError logs through a wrapper skipping its own frame.
+func logError(h *Helper) {
+	h.AddCallerSkip(1).Error("msg")
+}
+
+func TestLogger_StacktraceSkip(t *testing.T) {
+	r := &fieldsRecorder{}
+	logger := NewLogger(r)
+	logger.SetStacktrace(LevelError)
+	h := New(context.Background(), logger)
+
+	h.Errorw("msg")
+	st, _ := stackOf(r.kv)
+	if fn := topFunction(st); fn != "github.com/mengdu/mo.TestLogger_StacktraceSkip" {
+		t.Errorf("Helper stack trace starts at %s", fn)
+	}
+
+	logError(h)
+	st, _ = stackOf(r.kv)
+	if fn := topFunction(st); fn != "github.com/mengdu/mo.TestLogger_StacktraceSkip" {
+		t.Errorf("AddCallerSkip stack trace starts at %s", fn)
+	}
+}
+
+// stackHere returns the stack trace of its caller.
+func stackHere() Field {
+	return StackSkip(1)
+}
+
+func TestStack(t *testing.T) {
+	f := Stack()
+	if f.Key() != StacktraceKey || f.Type() != StackType {
+		t.Fatalf("Stack() = %s of type %d", f.Key(), f.Type())
+	}
+	if fn := topFunction(f.Stacktrace()); fn != "github.com/mengdu/mo.TestStack" {
+		t.Errorf("Stack() starts at %s", fn)
+	}
+	if fn := topFunction(stackHere().Stacktrace()); fn != "github.com/mengdu/mo.TestStack" {
+		t.Errorf("StackSkip(1) starts at %s", fn)
+	}
+
+	// Each frame is the function followed by the indented file and line.
+	lines := strings.Split(f.Stacktrace().String(), "\n")
+	if len(lines) < 2 || len(lines)%2 != 0 {
+		t.Fatalf("unexpected stack trace %q", f.Stacktrace().String())
+	}
+	if lines[0] != "github.com/mengdu/mo.TestStack" || !strings.HasPrefix(lines[1], "\t") || !strings.Contains(lines[1], "stack_test.go:") {
+		t.Errorf("unexpected first frame %q", lines[:2])
+	}
+	if string(f.AppendValue(nil)) != f.Stacktrace().String() {
+		t.Error("AppendValue should format the stack trace")
+	}
+	if Stacktrace(nil).String() != "" || Stacktrace(nil).Frames() != nil {
+		t.Error("empty stack trace should have no frames")
+	}
+}
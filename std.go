@@ -2,189 +2,350 @@ package mo
 
 import (
 	"context"
-	"os"
+	"sync/atomic"
 )
 
-// std is the default Logger instance used by the package-level logging functions.
+// std is the initial default Helper used by the package-level logging functions.
 var std = New(context.Background(), NewLogger(DefaultRecorder))
 
+// defaultHelper holds the current default Helper, *Helper.
+var defaultHelper atomic.Value
+
+func init() {
+	defaultHelper.Store(std)
+}
+
+// Default returns the default Helper used by the package-level logging functions.
+func Default() *Helper {
+	return defaultHelper.Load().(*Helper)
+}
+
+// SetDefault makes h the default Helper used by the package-level logging functions,
+// including the setters such as SetLevel. A nil h restores the initial default Helper.
+func SetDefault(h *Helper) {
+	if h == nil {
+		h = std
+	}
+	defaultHelper.Store(h)
+}
+
 // With returns a new Helper instance with the specified context.
 func With(ctx context.Context) *Helper {
-	return std.With(ctx)
+	return Default().With(ctx)
+}
+
+// WithFields returns a new Helper instance that adds the given key-value pairs to all log messages.
+func WithFields(kv ...Field) *Helper {
+	return Default().WithFields(kv...)
+}
+
+// Named returns a new Helper instance whose logger has the given name.
+func Named(name string) *Helper {
+	return Default().Named(name)
 }
 
 // Enabled returns whether logging at the specified level is enabled for the default logger.
 func Enabled(level Level) bool {
-	return std.Logger.Enabled(level)
+	return Default().Logger.Enabled(level)
 }
 
 // SetRecorder sets the recorder for the default logger.
 func SetRecorder(out Recorder) {
-	std.Logger.SetRecorder(out)
+	Default().Logger.SetRecorder(out)
+}
+
+// Use adds middlewares wrapping the recorder of the default logger, see Logger.Use.
+func Use(mw ...Middleware) {
+	Default().Logger.Use(mw...)
 }
 
 // SetLevel sets the log level for the default logger.
 func SetLevel(level Level) {
-	std.Logger.SetLevel(level)
+	Default().Logger.SetLevel(level)
+}
+
+// SetLevels sets the per-name level overrides for the default logger.
+func SetLevels(levels *Levels) {
+	Default().Logger.SetLevels(levels)
+}
+
+// SetCaller sets whether the default logger adds the caller of each log call to log messages.
+func SetCaller(enabled bool) {
+	Default().Logger.SetCaller(enabled)
+}
+
+// SetStacktrace sets the minimum level of log messages of the default logger that include a stack trace.
+func SetStacktrace(level Level) {
+	Default().Logger.SetStacktrace(level)
+}
+
+// SetExitFunc sets the function called by the package-level Fatal functions after logging.
+func SetExitFunc(fn func(code int)) {
+	Default().Logger.SetExitFunc(fn)
+}
+
+// Sync flushes the log messages buffered by the recorder of the default logger.
+// Applications should call it before exiting.
+func Sync() error {
+	return Default().Logger.Sync()
+}
+
+// Close flushes and closes the recorder of the default logger.
+func Close(ctx context.Context) error {
+	return Default().Logger.Close(ctx)
 }
 
 // SetBase sets the base key-value pairs for the default logger.
 func SetBase(kv ...Field) {
-	std.Logger.SetBase(kv...)
+	Default().Logger.SetBase(kv...)
+}
+
+// Log logs a message at the given level.
+func Log(level Level, a ...interface{}) {
+	h := Default()
+	h.Logger.print(h.ctx, level, a, nil)
+}
+
+// Logf logs a formatted message at the given level.
+func Logf(level Level, format string, a ...interface{}) {
+	h := Default()
+	h.Logger.printf(h.ctx, level, format, a, nil)
+}
+
+// Logw logs a message with key-value pairs at the given level.
+func Logw(level Level, msg string, kv ...Field) {
+	h := Default()
+	h.Logger.printw(h.ctx, level, msg, kv)
+}
+
+// Logx logs a message at the given level with the given context.
+func Logx(ctx context.Context, level Level, a ...interface{}) {
+	Default().Logger.print(ctx, level, a, nil)
+}
+
+// Logfx logs a formatted message at the given level with the given context.
+func Logfx(ctx context.Context, level Level, format string, a ...interface{}) {
+	Default().Logger.printf(ctx, level, format, a, nil)
+}
+
+// Logwx logs a message with key-value pairs at the given level with the given context.
+func Logwx(ctx context.Context, level Level, msg string, kv ...Field) {
+	Default().Logger.printw(ctx, level, msg, kv)
 }
 
 // Debug logs a message at the debug level.
 func Debug(a ...interface{}) {
-	std.Logger.Print(std.ctx, LevelDebug, a...)
+	h := Default()
+	h.Logger.print(h.ctx, LevelDebug, a, nil)
 }
 
 // Debugf logs a formatted message at the debug level.
 func Debugf(format string, a ...interface{}) {
-	std.Logger.Printf(std.ctx, LevelDebug, format, a...)
+	h := Default()
+	h.Logger.printf(h.ctx, LevelDebug, format, a, nil)
 }
 
 // Debugw logs a message with key-value pairs at the debug level.
 func Debugw(msg string, kv ...Field) {
-	std.Logger.Printw(std.ctx, LevelDebug, msg, kv...)
+	h := Default()
+	h.Logger.printw(h.ctx, LevelDebug, msg, kv)
 }
 
 // Debugx logs a message at the debug level with the given context.
 func Debugx(ctx context.Context, a ...interface{}) {
-	std.Logger.Print(ctx, LevelDebug, a...)
+	Default().Logger.print(ctx, LevelDebug, a, nil)
 }
 
 // Debugfx logs a formatted message at the debug level with the given context.
 func Debugfx(ctx context.Context, format string, a ...interface{}) {
-	std.Logger.Printf(ctx, LevelDebug, format, a...)
+	Default().Logger.printf(ctx, LevelDebug, format, a, nil)
 }
 
 // Debugwx logs a message with key-value pairs at the debug level with the given context.
 func Debugwx(ctx context.Context, msg string, kv ...Field) {
-	std.Logger.Printw(ctx, LevelDebug, msg, kv...)
+	Default().Logger.printw(ctx, LevelDebug, msg, kv)
 }
 
 // Info logs a message at the info level.
 func Info(a ...interface{}) {
-	std.Logger.Print(std.ctx, LevelInfo, a...)
+	h := Default()
+	h.Logger.print(h.ctx, LevelInfo, a, nil)
 }
 
 // Infof logs a formatted message at the info level.
 func Infof(format string, a ...interface{}) {
-	std.Logger.Printf(std.ctx, LevelInfo, format, a...)
+	h := Default()
+	h.Logger.printf(h.ctx, LevelInfo, format, a, nil)
 }
 
 // Infow logs a message with key-value pairs at the info level.
 func Infow(msg string, kv ...Field) {
-	std.Logger.Printw(std.ctx, LevelInfo, msg, kv...)
+	h := Default()
+	h.Logger.printw(h.ctx, LevelInfo, msg, kv)
 }
 
 // Infox logs a message at the info level with the given context.
 func Infox(ctx context.Context, a ...interface{}) {
-	std.Logger.Print(ctx, LevelInfo, a...)
+	Default().Logger.print(ctx, LevelInfo, a, nil)
 }
 
 // Infofx logs a formatted message at the info level with the given context.
 func Infofx(ctx context.Context, format string, a ...interface{}) {
-	std.Logger.Printf(ctx, LevelInfo, format, a...)
+	Default().Logger.printf(ctx, LevelInfo, format, a, nil)
 }
 
 // Infowx logs a message with key-value pairs at the info level with the given context.
 func Infowx(ctx context.Context, msg string, kv ...Field) {
-	std.Logger.Printw(ctx, LevelInfo, msg, kv...)
+	Default().Logger.printw(ctx, LevelInfo, msg, kv)
 }
 
 // Warn logs a message at the warn level.
 func Warn(a ...interface{}) {
-	std.Logger.Print(std.ctx, LevelWarn, a...)
+	h := Default()
+	h.Logger.print(h.ctx, LevelWarn, a, nil)
 }
 
 // Warnf logs a formatted message at the warn level.
 func Warnf(format string, a ...interface{}) {
-	std.Logger.Printf(std.ctx, LevelWarn, format, a...)
+	h := Default()
+	h.Logger.printf(h.ctx, LevelWarn, format, a, nil)
 }
 
 // Warnw logs a message with key-value pairs at the warn level.
 func Warnw(msg string, kv ...Field) {
-	std.Logger.Printw(std.ctx, LevelWarn, msg, kv...)
+	h := Default()
+	h.Logger.printw(h.ctx, LevelWarn, msg, kv)
 }
 
 // Warnx logs a message at the warn level with the given context.
 func Warnx(ctx context.Context, a ...interface{}) {
-	std.Logger.Print(ctx, LevelWarn, a...)
+	Default().Logger.print(ctx, LevelWarn, a, nil)
 }
 
 // Warnfx logs a formatted message at the warn level with the given context.
 func Warnfx(ctx context.Context, format string, a ...interface{}) {
-	std.Logger.Printf(ctx, LevelWarn, format, a...)
+	Default().Logger.printf(ctx, LevelWarn, format, a, nil)
 }
 
 // Warnwx logs a message with key-value pairs at the warn level with the given context.
 func Warnwx(ctx context.Context, msg string, kv ...Field) {
-	std.Logger.Printw(ctx, LevelWarn, msg, kv...)
+	Default().Logger.printw(ctx, LevelWarn, msg, kv)
 }
 
 // Error logs a message at the error level.
 func Error(a ...interface{}) {
-	std.Logger.Print(std.ctx, LevelError, a...)
+	h := Default()
+	h.Logger.print(h.ctx, LevelError, a, nil)
 }
 
 // Errorf logs a formatted message at the error level.
 func Errorf(format string, a ...interface{}) {
-	std.Logger.Printf(std.ctx, LevelError, format, a...)
+	h := Default()
+	h.Logger.printf(h.ctx, LevelError, format, a, nil)
 }
 
 // Errorw logs a message with key-value pairs at the error level.
 func Errorw(msg string, kv ...Field) {
-	std.Logger.Printw(std.ctx, LevelError, msg, kv...)
+	h := Default()
+	h.Logger.printw(h.ctx, LevelError, msg, kv)
 }
 
 // Errorx logs a message at the error level with the given context.
 func Errorx(ctx context.Context, a ...interface{}) {
-	std.Logger.Print(ctx, LevelError, a...)
+	Default().Logger.print(ctx, LevelError, a, nil)
 }
 
 // Errorfx logs a formatted message at the error level with the given context.
 func Errorfx(ctx context.Context, format string, a ...interface{}) {
-	std.Logger.Printf(ctx, LevelError, format, a...)
+	Default().Logger.printf(ctx, LevelError, format, a, nil)
 }
 
 // Errorwx logs a message with key-value pairs at the error level with the given context.
 func Errorwx(ctx context.Context, msg string, kv ...Field) {
-	std.Logger.Printw(ctx, LevelError, msg, kv...)
+	Default().Logger.printw(ctx, LevelError, msg, kv)
+}
+
+// Panic logs a message at the panic level and panics.
+func Panic(a ...interface{}) {
+	h := Default()
+	msg := sprint(a...)
+	h.Logger.printw(h.ctx, LevelPanic, msg, nil)
+	panic(msg)
+}
+
+// Panicf logs a formatted message at the panic level and panics.
+func Panicf(format string, a ...interface{}) {
+	h := Default()
+	msg := sprintf(format, a...)
+	h.Logger.printw(h.ctx, LevelPanic, msg, nil)
+	panic(msg)
+}
+
+// Panicw logs a message with key-value pairs at the panic level and panics.
+func Panicw(msg string, kv ...Field) {
+	h := Default()
+	h.Logger.printw(h.ctx, LevelPanic, msg, kv)
+	panic(msg)
+}
+
+// Panicx logs a message at the panic level with the given context and panics.
+func Panicx(ctx context.Context, a ...interface{}) {
+	msg := sprint(a...)
+	Default().Logger.printw(ctx, LevelPanic, msg, nil)
+	panic(msg)
+}
+
+// Panicfx logs a formatted message at the panic level with the given context and panics.
+func Panicfx(ctx context.Context, format string, a ...interface{}) {
+	msg := sprintf(format, a...)
+	Default().Logger.printw(ctx, LevelPanic, msg, nil)
+	panic(msg)
+}
+
+// Panicwx logs a message with key-value pairs at the panic level with the given context and panics.
+func Panicwx(ctx context.Context, msg string, kv ...Field) {
+	Default().Logger.printw(ctx, LevelPanic, msg, kv)
+	panic(msg)
 }
 
 // Fatal logs a message at the fatal level and exits the program.
 func Fatal(a ...interface{}) {
-	std.Logger.Print(std.ctx, LevelFatal, a...)
-	os.Exit(1)
+	h := Default()
+	h.Logger.print(h.ctx, LevelFatal, a, nil)
+	h.Logger.exit(1)
 }
 
 // Fatalf logs a formatted message at the fatal level and exits the program.
 func Fatalf(format string, a ...interface{}) {
-	std.Logger.Printf(std.ctx, LevelFatal, format, a...)
-	os.Exit(1)
+	h := Default()
+	h.Logger.printf(h.ctx, LevelFatal, format, a, nil)
+	h.Logger.exit(1)
 }
 
 // Fatalw logs a message with key-value pairs at the fatal level and exits the program.
 func Fatalw(msg string, kv ...Field) {
-	std.Logger.Printw(std.ctx, LevelFatal, msg, kv...)
-	os.Exit(1)
+	h := Default()
+	h.Logger.printw(h.ctx, LevelFatal, msg, kv)
+	h.Logger.exit(1)
 }
 
 // Fatalx logs a message at the fatal level with the given context and exits the program.
 func Fatalx(ctx context.Context, a ...interface{}) {
-	std.Logger.Print(ctx, LevelFatal, a...)
-	os.Exit(1)
+	h := Default()
+	h.Logger.print(ctx, LevelFatal, a, nil)
+	h.Logger.exit(1)
 }
 
 // Fatalfx logs a formatted message at the fatal level with the given context and exits the program.
 func Fatalfx(ctx context.Context, format string, a ...interface{}) {
-	std.Logger.Printf(ctx, LevelFatal, format, a...)
-	os.Exit(1)
+	h := Default()
+	h.Logger.printf(ctx, LevelFatal, format, a, nil)
+	h.Logger.exit(1)
 }
 
 // Fatalwx logs a message with key-value pairs at the fatal level with the given context and exits the program.
 func Fatalwx(ctx context.Context, msg string, kv ...Field) {
-	std.Logger.Printw(ctx, LevelFatal, msg, kv...)
-	os.Exit(1)
+	h := Default()
+	h.Logger.printw(ctx, LevelFatal, msg, kv)
+	h.Logger.exit(1)
 }
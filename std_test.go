@@ -0,0 +1,31 @@
+package mo
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSetDefault(t *testing.T) {
+	initial := Default()
+	defer SetDefault(initial)
+
+	r := &fieldsRecorder{}
+	h := New(context.Background(), NewLogger(r))
+	SetDefault(h)
+	if Default() != h {
+		t.Fatal("Default() should return the Helper set by SetDefault")
+	}
+	Info("msg")
+	SetLevel(LevelWarn)
+	if r.msg != "msg" || h.Logger.Level() != LevelWarn {
+		t.Error("package-level functions should use the new default Helper")
+	}
+
+	SetDefault(nil)
+	if Default() != std || Default() != initial {
+		t.Error("SetDefault(nil) should restore the initial default Helper")
+	}
+	if Enabled(LevelInfo) != std.Logger.Enabled(LevelInfo) || Default().Logger == h.Logger {
+		t.Error("the previous default Helper is still used")
+	}
+}
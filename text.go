@@ -0,0 +1,137 @@
+package mo
+
+import (
+	"bytes"
+	"strings"
+)
+
+// timestampKey is the key of the field written first in brackets by TextFormat.
+const timestampKey = "ts"
+
+// TextPart identifies a part of a line written by TextFormat, for coloring it.
+type TextPart int
+
+const (
+	// TextTimestamp is the value of the "ts" field.
+	TextTimestamp TextPart = iota
+	// TextLevel is the level tag, such as "[INF]".
+	TextLevel
+	// TextMessage is the log message.
+	TextMessage
+	// TextKey is the key of a field.
+	TextKey
+	// TextCaller is the value of the CallerKey field.
+	TextCaller
+	// TextDetail is a line of the causes of errors and of stack traces.
+	TextDetail
+)
+
+// TextFormat writes entries as human readable text lines, such as
+// "[ts][INF] message k1=v1, k2=v2 caller". Errors are written with their type
+// and fields, their causes and stack traces as indented blocks below the line.
+// It is used by DefaultRecorder and by the console recorders of the record package.
+type TextFormat struct {
+	LevelTag         func(level Level) string                          // Level tag inside the brackets, Level.Abbr if nil
+	FilterEmptyField bool                                              // Omit fields with empty values
+	Paint            func(part TextPart, level Level, s string) string // Colors the parts of the line, if not nil
+}
+
+func (f TextFormat) paint(part TextPart, level Level, s string) string {
+	if f.Paint == nil {
+		return s
+	}
+	return f.Paint(part, level, s)
+}
+
+// Write writes the entry to buf, including the line terminator.
+func (f TextFormat) Write(buf *bytes.Buffer, e Entry) {
+	var ts, caller string
+	for _, v := range e.Fields {
+		switch v.Key() {
+		case timestampKey:
+			ts = v.Text()
+		case CallerKey:
+			caller = v.Text()
+		}
+	}
+
+	if ts != "" {
+		buf.WriteByte('[')
+		buf.WriteString(f.paint(TextTimestamp, e.Level, ts))
+		buf.WriteByte(']')
+	}
+	tag := e.Level.Abbr()
+	if f.LevelTag != nil {
+		tag = f.LevelTag(e.Level)
+	}
+	if f.Paint != nil {
+		buf.WriteString(f.Paint(TextLevel, e.Level, "["+tag+"]"))
+	} else {
+		buf.WriteByte('[')
+		buf.WriteString(tag)
+		buf.WriteByte(']')
+	}
+	buf.WriteByte(' ')
+	buf.WriteString(f.paint(TextMessage, e.Level, e.Message))
+
+	i := 0
+	writeField := func(key, val string) {
+		if f.FilterEmptyField && val == "" {
+			return
+		}
+		if i > 0 {
+			buf.WriteString(", ")
+		} else {
+			buf.WriteByte(' ')
+		}
+		buf.WriteString(f.paint(TextKey, e.Level, key))
+		buf.WriteByte('=')
+		buf.WriteString(val)
+		i++
+	}
+	var causes []ErrorCause
+	for _, v := range e.Fields {
+		if v.Key() == timestampKey || v.Key() == CallerKey || v.Type() == StackType {
+			continue
+		}
+		if v.Type() != ErrorType {
+			writeField(v.Key(), v.Text())
+			continue
+		}
+
+		d := DescribeError(v.Err())
+		writeField(v.Key(), d.Message+" ["+d.Type+"]")
+		for _, fv := range d.Fields {
+			writeField(v.Key()+"."+fv.Key(), fv.Text())
+		}
+		causes = append(causes, d.Causes...)
+	}
+	if caller != "" {
+		buf.WriteByte(' ')
+		buf.WriteString(f.paint(TextCaller, e.Level, caller))
+	}
+	buf.WriteByte('\n')
+	for _, c := range causes {
+		f.writeDetail(buf, e.Level, "caused by ["+c.Type+"]: "+c.Message)
+	}
+	for _, v := range e.Fields {
+		if v.Type() == StackType {
+			f.writeDetail(buf, e.Level, v.Stacktrace().String())
+		}
+	}
+}
+
+// writeDetail writes each line of s to buf, indented.
+func (f TextFormat) writeDetail(buf *bytes.Buffer, level Level, s string) {
+	for len(s) > 0 {
+		line := s
+		if idx := strings.IndexByte(s, '\n'); idx != -1 {
+			line, s = s[:idx], s[idx+1:]
+		} else {
+			s = ""
+		}
+		buf.WriteString("    ")
+		buf.WriteString(f.paint(TextDetail, level, line))
+		buf.WriteByte('\n')
+	}
+}
@@ -0,0 +1,47 @@
+package mo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestTextFormat(t *testing.T) {
+	err := fmt.Errorf("load: %w", fieldsError{errors.New("timeout")})
+	e := Entry{Level: LevelError, Message: "failed", Fields: []Field{
+		String("ts", "2024-10-18"),
+		String("table", "users"),
+		String("empty", ""),
+		Err(err),
+		String(CallerKey, "db/query.go:42"),
+	}}
+
+	var buf bytes.Buffer
+	TextFormat{}.Write(&buf, e)
+	want := "[2024-10-18][ERR] failed table=users, empty=, error=load: query: timeout [*fmt.wrapError], error.code=42 db/query.go:42\n" +
+		"    caused by [mo.fieldsError]: query: timeout\n" +
+		"    caused by [*errors.errorString]: timeout\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got\n%swant\n%s", got, want)
+	}
+
+	buf.Reset()
+	f := TextFormat{
+		LevelTag:         func(level Level) string { return level.String() },
+		FilterEmptyField: true,
+		Paint: func(part TextPart, level Level, s string) string {
+			return fmt.Sprintf("<%d:%s>", part, s)
+		},
+	}
+	f.Write(&buf, Entry{Level: LevelInfo, Message: "msg", Fields: []Field{String("empty", ""), Int("n", 1)}})
+	if got := buf.String(); got != "<1:[INFO]> <2:msg> <3:n>=1\n" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestField_Text(t *testing.T) {
+	if String("k", "v").Text() != "v" || Int("k", 42).Text() != "42" || Any("k", []int{1}).Text() != "[1]" {
+		t.Error("unexpected text")
+	}
+}